@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	fl "github.com/karetskiiVO/FormalLanguages/formallang"
+)
+
+type automaton interface {
+	WriteText(w io.Writer) error
+	WriteDOT(w io.Writer) error
+	Render(w io.Writer, format fl.Format) error
+}
+
+var formats = map[string]fl.Format{
+	"dot": fl.FormatDOT,
+	"png": fl.FormatPNG,
+	"svg": fl.FormatSVG,
+	"jpg": fl.FormatJPG,
+}
+
+func parseRegExp(str string, rpn, extended bool) (*fl.RegExp, error) {
+	if rpn {
+		return fl.RegExpFromRPN(str)
+	}
+
+	syntax := fl.SyntaxClassic
+	if extended {
+		syntax = fl.SyntaxExtended
+	}
+
+	tokens, err := fl.TokenizeWithSyntax(str, syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	return fl.RegExpFromTokensWithSyntax(tokens, syntax)
+}
+
+func writeAutomaton(a automaton, w io.Writer, format string) error {
+	switch format {
+	case "text":
+		return a.WriteText(w)
+	case "json":
+		data, err := json.MarshalIndent(a, "", "\t")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	if f, ok := formats[format]; ok {
+		return a.Render(w, f)
+	}
+
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// output - writes automaton into file, stdout if filename is empty
+func output(a automaton, filename, format string) (err error) {
+	if filename == "" {
+		return writeAutomaton(a, os.Stdout, format)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return writeAutomaton(a, file, format)
+}
+
+func build(reg *fl.RegExp, to string) (automaton, error) {
+	if to == "nfa" {
+		return fl.NFAFromRegExp(reg), nil
+	}
+
+	dfa := fl.DFAfromNFA(fl.NFAFromRegExp(reg).RemoveEmpty())
+
+	switch to {
+	case "dfa":
+		return dfa, nil
+	case "cdfa":
+		return fl.CDFAfromDFA(dfa), nil
+	case "mcdfa":
+		return fl.CDFAfromDFA(dfa).Minimise(), nil
+	}
+
+	return nil, fmt.Errorf("unknown automaton type %q", to)
+}
+
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	rpn := flags.Bool("rpn", false, "regexp is in reverse polish notation")
+	extended := flags.Bool("extended", false, "regexp uses | for alternation and postfix +, ?, {n,m}")
+	to := flags.String("to", "mcdfa", "automaton type: nfa, dfa, cdfa or mcdfa")
+	format := flags.String("format", "text", "output format: text, json, dot, png, svg or jpg")
+	out := flags.String("o", "", "output file, stdout by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected one regexp, got %v arguments", flags.NArg())
+	}
+
+	reg, err := parseRegExp(flags.Arg(0), *rpn, *extended)
+	if err != nil {
+		return err
+	}
+
+	a, err := build(reg, *to)
+	if err != nil {
+		return err
+	}
+
+	return output(a, *out, *format)
+}
+
+func runMatch(args []string) error {
+	flags := flag.NewFlagSet("match", flag.ContinueOnError)
+	rpn := flags.Bool("rpn", false, "regexp is in reverse polish notation")
+	extended := flags.Bool("extended", false, "regexp uses | for alternation and postfix +, ?, {n,m}")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected one regexp, got %v arguments", flags.NArg())
+	}
+
+	reg, err := parseRegExp(flags.Arg(0), *rpn, *extended)
+	if err != nil {
+		return err
+	}
+
+	dfa := fl.DFAfromNFA(fl.NFAFromRegExp(reg).RemoveEmpty())
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		word := scanner.Text()
+
+		res := "no"
+		if dfa.Accepts(word) {
+			res = "yes"
+		}
+
+		fmt.Printf("%s\t%s\n", word, res)
+	}
+
+	return scanner.Err()
+}
+
+func runEquiv(args []string) error {
+	flags := flag.NewFlagSet("equiv", flag.ContinueOnError)
+	rpn := flags.Bool("rpn", false, "regexps are in reverse polish notation")
+	extended := flags.Bool("extended", false, "regexps use | for alternation and postfix +, ?, {n,m}")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected two regexps, got %v arguments", flags.NArg())
+	}
+
+	dfas := make([]*fl.DFA, 2)
+	for i := range dfas {
+		reg, err := parseRegExp(flags.Arg(i), *rpn, *extended)
+		if err != nil {
+			return err
+		}
+
+		dfas[i] = fl.DFAfromNFA(fl.NFAFromRegExp(reg).RemoveEmpty())
+	}
+
+	if ok, word := fl.Equivalent(dfas[0], dfas[1]); !ok {
+		fmt.Printf("not equivalent, distinguishing word %q\n", word)
+		return nil
+	}
+
+	fmt.Println("equivalent")
+	return nil
+}
+
+// readNFA - reads automaton in text or json format, json is detected by extension or first symbol
+func readNFA(filename string) (*fl.NFA, error) {
+	var data []byte
+	var err error
+	if filename == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Ext(filename) == ".json" || strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		nfa := &fl.NFA{}
+		if err := json.Unmarshal(data, nfa); err != nil {
+			return nil, err
+		}
+		return nfa, nil
+	}
+
+	return fl.ParseNFA(strings.NewReader(string(data)))
+}
+
+func runMin(args []string) error {
+	flags := flag.NewFlagSet("min", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text, json, dot, png, svg or jpg")
+	out := flags.String("o", "", "output file, stdout by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected one automaton file, got %v arguments", flags.NArg())
+	}
+
+	nfa, err := readNFA(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	mcdfa := fl.CDFAfromDFA(fl.DFAfromNFA(nfa.RemoveEmpty())).Minimise()
+	return output(mcdfa, *out, *format)
+}
+
+func runStages(args []string) error {
+	flags := flag.NewFlagSet("stages", flag.ContinueOnError)
+	rpn := flags.Bool("rpn", false, "regexp is in reverse polish notation")
+	extended := flags.Bool("extended", false, "regexp uses | for alternation and postfix +, ?, {n,m}")
+	format := flags.String("format", "png", "image format: png, svg, jpg or dot")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected regexp and directory, got %v arguments", flags.NArg())
+	}
+
+	if _, ok := formats[*format]; !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	reg, err := parseRegExp(flags.Arg(0), *rpn, *extended)
+	if err != nil {
+		return err
+	}
+
+	dir := flags.Arg(1)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	nfa := fl.NFAFromRegExp(reg)
+	nfaWithoutEmpty := nfa.WithoutEpsilon()
+	dfa := fl.DFAfromNFA(nfaWithoutEmpty)
+	cdfa := fl.CDFAfromDFA(dfa)
+	mcdfa := cdfa.Minimise()
+
+	stages := []struct {
+		name string
+		a    automaton
+	}{
+		{"0_nfa", nfa},
+		{"1_nfa_without_empty", nfaWithoutEmpty},
+		{"2_dfa", dfa},
+		{"3_cdfa", cdfa},
+		{"4_mcdfa", mcdfa},
+	}
+
+	for _, stage := range stages {
+		filename := filepath.Join(dir, stage.name+"."+*format)
+		if err := output(stage.a, filename, *format); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runPrefix(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected no arguments, got %v arguments", len(args))
+	}
+
+	var pol string
+	var r rune
+	var k int
+
+	if _, err := fmt.Scanf("%s %c %d", &pol, &r, &k); err != nil {
+		return err
+	}
+	if k < 0 {
+		return fmt.Errorf("power must be non-negative, got %v", k)
+	}
+
+	reg, err := fl.RegExpFromRPN(pol)
+	if err != nil {
+		return err
+	}
+
+	dfa := fl.DFAfromNFA(fl.NFAFromRegExp(reg).RemoveEmpty())
+
+	if res, ok := dfa.MinLenWithPrefixPower(r, k); ok {
+		fmt.Println(res)
+	} else {
+		fmt.Println("INF")
+	}
+
+	return nil
+}
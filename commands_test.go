@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fl "github.com/karetskiiVO/FormalLanguages/formallang"
+)
+
+func TestParseRegExp(t *testing.T) {
+	tests := []struct {
+		str           string
+		rpn, extended bool
+		want          string
+		ok            bool
+	}{
+		{"(a + b)*", false, false, "(a + b)*", true},
+		{"a|b+", false, true, "a | b+", true},
+		{"ab+*", true, false, "(a + b)*", true},
+		{"a+", false, false, "", false},
+		{"a{3,1}", false, true, "", false},
+		{"a+", true, false, "", false},
+		{`a\`, false, false, "", false},
+	}
+
+	for _, test := range tests {
+		reg, err := parseRegExp(test.str, test.rpn, test.extended)
+		if (err == nil) != test.ok {
+			t.Errorf("%q rpn=%v extended=%v: got error %v", test.str, test.rpn, test.extended, err)
+			continue
+		}
+
+		if err == nil && reg.ToString() != test.want {
+			t.Errorf("%q rpn=%v extended=%v: got %v, want %v", test.str, test.rpn, test.extended, reg.ToString(), test.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	reg, err := parseRegExp("a(a + b)*", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		to   string
+		want any
+	}{
+		{"nfa", &fl.NFA{}},
+		{"dfa", &fl.DFA{}},
+		{"cdfa", &fl.CDFA{}},
+		{"mcdfa", &fl.CDFA{}},
+	}
+
+	for _, test := range tests {
+		a, err := build(reg, test.to)
+		if err != nil {
+			t.Errorf("%v: %v", test.to, err)
+			continue
+		}
+
+		if got, want := typeName(a), typeName(test.want); got != want {
+			t.Errorf("%v: got %v, want %v", test.to, got, want)
+		}
+	}
+
+	if _, err := build(reg, "pda"); err == nil {
+		t.Errorf("unknown automaton type is built")
+	}
+}
+
+func typeName(a any) string {
+	switch a.(type) {
+	case *fl.NFA:
+		return "NFA"
+	case *fl.DFA:
+		return "DFA"
+	case *fl.CDFA:
+		return "CDFA"
+	}
+
+	return "unknown"
+}
+
+func TestWriteAutomaton(t *testing.T) {
+	reg, err := parseRegExp("ab", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := build(reg, "mcdfa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format, prefix string
+	}{
+		{"text", "a b\nq0\n"},
+		{"json", "{"},
+		{"dot", "digraph"},
+		{"svg", "<?xml"},
+	}
+
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		if err := writeAutomaton(a, buf, test.format); err != nil {
+			t.Errorf("%v: %v", test.format, err)
+			continue
+		}
+
+		if !strings.HasPrefix(buf.String(), test.prefix) {
+			t.Errorf("%v: output starts with %.20q, want %q", test.format, buf.String(), test.prefix)
+		}
+	}
+
+	if err := writeAutomaton(a, &bytes.Buffer{}, "gif"); err == nil {
+		t.Errorf("unknown format is written")
+	}
+}
+
+func TestRunMin(t *testing.T) {
+	reg, err := parseRegExp("a*a*", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nfa := fl.NFAFromRegExp(reg)
+
+	text := &bytes.Buffer{}
+	if err := nfa.WriteText(text); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(nfa)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	inputs := map[string][]byte{
+		"nfa.txt":  text.Bytes(),
+		"nfa.json": data,
+	}
+
+	for name, content := range inputs {
+		in := filepath.Join(dir, name)
+		if err := os.WriteFile(in, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		out := filepath.Join(dir, name+".out")
+		if err := runMin([]string{"-o", out, in}); err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+
+		got, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// minimal CDFA of a* has one state besides sink q1
+		if want := "a\nq0\nq0\nq0 a q0\nq1 a q1\n"; string(got) != want {
+			t.Errorf("%v: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRunStages(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "stages")
+	if err := runStages([]string{"-format", "dot", "(a + b)*a", dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"0_nfa", "1_nfa_without_empty", "2_dfa", "3_cdfa", "4_mcdfa"} {
+		data, err := os.ReadFile(filepath.Join(dir, name+".dot"))
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+
+		if !strings.HasPrefix(string(data), "digraph") {
+			t.Errorf("%v: not a DOT file", name)
+		}
+	}
+}
+
+func TestCommandArgumentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(args []string) error
+		args []string
+	}{
+		{"convert without regexp", runConvert, nil},
+		{"convert unknown flag", runConvert, []string{"-x", "a"}},
+		{"convert unknown type", runConvert, []string{"-to", "pda", "a"}},
+		{"convert unknown format", runConvert, []string{"-format", "gif", "a"}},
+		{"match two regexps", runMatch, []string{"a", "b"}},
+		{"equiv one regexp", runEquiv, []string{"a"}},
+		{"min missing file", runMin, []string{filepath.Join(t.TempDir(), "missing")}},
+		{"stages unknown format", runStages, []string{"-format", "gif", "a", t.TempDir()}},
+		{"prefix with arguments", runPrefix, []string{"ab+"}},
+	}
+
+	for _, test := range tests {
+		if err := test.run(test.args); err == nil {
+			t.Errorf("%v: expected error", test.name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package formallang
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// goRegexp - translates classic syntax without escapes into Go regexp
+func goRegexp(t *testing.T, str string) *regexp.Regexp {
+	t.Helper()
+
+	str = strings.NewReplacer("+", "|", "1", "(?:)", " ", "").Replace(str)
+	return regexp.MustCompile("^(?:" + str + ")$")
+}
+
+func TestAcceptsAgree(t *testing.T) {
+	for dir, input := range stageInputs(t) {
+		reg := mustRegExp(t, input)
+		want := goRegexp(t, input)
+
+		nfa := NFAFromRegExp(reg)
+		nfaWithoutEmpty := NFAFromRegExp(reg).RemoveEmpty()
+		dfa := DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())
+		cdfa := CDFAfromDFA(dfa)
+		mcdfa := cdfa.Minimise()
+
+		automata := []struct {
+			name    string
+			accepts func(string) bool
+		}{
+			{"nfa", nfa.Accepts},
+			{"nfa without empty", nfaWithoutEmpty.Accepts},
+			{"dfa", dfa.Accepts},
+			{"cdfa", cdfa.Accepts},
+			{"mcdfa", mcdfa.Accepts},
+		}
+
+		// c is out of alphabet of every input
+		for _, word := range words([]rune("abc"), 6) {
+			expected := want.MatchString(word)
+			for _, a := range automata {
+				if got := a.accepts(word); got != expected {
+					t.Errorf("%v: %v %q: %v accepts %v, want %v", dir, input, word, a.name, got, expected)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package formallang
+
+import (
+	"maps"
+	"sort"
+)
+
+// regExpNodeSet - set of expressions keyed by their canonical string
+type regExpNodeSet map[string]regExpNode
+
+func (set regExpNodeSet) add(node regExpNode) {
+	node = aciNormalize(node)
+	if _, ok := node.(regExpNodeEmptySet); ok {
+		return
+	}
+
+	set[regExpNodeKey(node)] = node
+}
+
+// concat - multiplies every expression of set by tail
+func (set regExpNodeSet) concat(tail regExpNode) regExpNodeSet {
+	res := make(regExpNodeSet, len(set))
+	for _, node := range set {
+		res.add(regExpNodeMul{[]regExpNode{node, tail}})
+	}
+
+	return res
+}
+
+func (regExpNodeEmptyRune) PartialDerivative(rune) regExpNodeSet { return regExpNodeSet{} }
+func (regExpNodeEmptySet) PartialDerivative(rune) regExpNodeSet  { return regExpNodeSet{} }
+
+func (r regExpNodeRune) PartialDerivative(by rune) regExpNodeSet {
+	res := regExpNodeSet{}
+	if r.r == by {
+		res.add(regExpNodeEmptyRune{})
+	}
+
+	return res
+}
+
+func (add regExpNodeAdd) PartialDerivative(by rune) regExpNodeSet {
+	res := regExpNodeSet{}
+	for _, next := range add.Next {
+		maps.Copy(res, next.PartialDerivative(by))
+	}
+
+	return res
+}
+
+func (mul regExpNodeMul) PartialDerivative(by rune) regExpNodeSet {
+	head, tail := mul.Next[0], mulOf(mul.Next[1:])
+
+	res := head.PartialDerivative(by).concat(tail)
+	if head.Nullable() {
+		maps.Copy(res, tail.PartialDerivative(by))
+	}
+
+	return res
+}
+
+func (clini regExpNodeClini) PartialDerivative(by rune) regExpNodeSet {
+	return clini.Next.PartialDerivative(by).concat(clini)
+}
+
+// AntimirovNFA - constructs NFA without empty transitions, which states are partial derivatives of RegExp
+func AntimirovNFA(reg *RegExp) *NFA {
+	nfa := &NFA{
+		abc:   maps.Clone(reg.abc),
+		nodes: make(map[*nfanode]struct{}),
+	}
+
+	alph := unionAlphabet(nfa.abc)
+	used := make(map[string]*nfanode)
+
+	var tasks queue
+	get := func(expr regExpNode) *nfanode {
+		key := regExpNodeKey(expr)
+		if node, ok := used[key]; ok {
+			return node
+		}
+
+		node := nfa.newNode()
+		node.endpoint = expr.Nullable()
+		used[key] = node
+
+		tasks.Push(expr)
+		return node
+	}
+
+	nfa.start = get(aciNormalize(reg.tree))
+	nfa.start.linkscnt++
+
+	for tasks.Size() > 0 {
+		expr := tasks.Top().(regExpNode)
+		tasks.Pop()
+
+		from := used[regExpNodeKey(expr)]
+		for _, r := range alph {
+			derivatives := expr.PartialDerivative(r)
+
+			keys := make([]string, 0, len(derivatives))
+			for key := range derivatives {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				from.link(r, get(derivatives[key]))
+			}
+		}
+	}
+
+	return nfa
+}
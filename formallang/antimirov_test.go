@@ -0,0 +1,18 @@
+package formallang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAntimirovStates(t *testing.T) {
+	for _, input := range regExpInputs(t, 300) {
+		nfa := AntimirovNFA(mustRegExp(t, input))
+
+		// Antimirov's bound: no more partial derivatives than positions of Glushkov NFA
+		positions := strings.Count(input, "a") + strings.Count(input, "b")
+		if len(nfa.nodes) > positions+1 {
+			t.Errorf("%v: Antimirov NFA has %v states, want at most %v", input, len(nfa.nodes), positions+1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package formallang
+
+// move - makes transition, symbols without transition lead to stock
+func (cdfa *CDFA) move(from *dfanode, r rune) *dfanode {
+	if to, ok := from.next[r]; ok {
+		return to
+	}
+
+	return cdfa.stock
+}
+
+// productCDFA - builds product automaton over merged alphabet, accept decides if pair of states is accepting
+func productCDFA(a, b *CDFA, accept func(a, b bool) bool) *CDFA {
+	product := &CDFA{
+		abc:   make(map[rune]struct{}),
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	abc := unionAlphabet(a.abc, b.abc)
+	for _, r := range abc {
+		product.abc[r] = struct{}{}
+	}
+
+	var tasks queue
+	pairs := make(map[dfanodePair]*dfanode)
+	get := func(pair dfanodePair) *dfanode {
+		if node, ok := pairs[pair]; ok {
+			return node
+		}
+
+		node := product.newNode()
+		node.endpoint = accept(pair.a.endpoint, pair.b.endpoint)
+		pairs[pair] = node
+
+		tasks.Push(pair)
+		return node
+	}
+
+	product.start = get(dfanodePair{a.start, b.start})
+
+	for tasks.Size() > 0 {
+		curr := tasks.Top().(dfanodePair)
+		tasks.Pop()
+
+		from := pairs[curr]
+		for _, r := range abc {
+			from.link(r, get(dfanodePair{a.move(curr.a, r), b.move(curr.b, r)}))
+		}
+	}
+
+	if stock, ok := pairs[dfanodePair{a.stock, b.stock}]; ok && !stock.endpoint {
+		product.stock = stock
+	} else {
+		product.stock = product.newNode()
+		for _, r := range abc {
+			product.stock.link(r, product.stock)
+		}
+	}
+
+	return product
+}
+
+// Complement - constructs CDFA accepting words over alphabet, that are not accepted by cdfa
+func (cdfa *CDFA) Complement() *CDFA {
+	return productCDFA(cdfa, cdfa, func(a, _ bool) bool { return !a })
+}
+
+// Intersect - constructs CDFA accepting words accepted by both automata
+func (cdfa *CDFA) Intersect(other *CDFA) *CDFA {
+	return productCDFA(cdfa, other, func(a, b bool) bool { return a && b })
+}
+
+// Union - constructs CDFA accepting words accepted by any of automata
+func (cdfa *CDFA) Union(other *CDFA) *CDFA {
+	return productCDFA(cdfa, other, func(a, b bool) bool { return a || b })
+}
+
+// Difference - constructs CDFA accepting words accepted by cdfa, but not by other
+func (cdfa *CDFA) Difference(other *CDFA) *CDFA {
+	return productCDFA(cdfa, other, func(a, b bool) bool { return a && !b })
+}
+
+// SymmetricDifference - constructs CDFA accepting words accepted by exactly one of automata
+func (cdfa *CDFA) SymmetricDifference(other *CDFA) *CDFA {
+	return productCDFA(cdfa, other, func(a, b bool) bool { return a != b })
+}
@@ -0,0 +1,63 @@
+package formallang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBooleanOperations(t *testing.T) {
+	pairs := []struct {
+		a, b string
+	}{
+		// alphabets are {a} and {b}
+		{"a*", "b*"},
+		{"a*b", "(a + b)*a"},
+		{"(ab + ba)*", "a(a + b)*"},
+		{"c", "ab*"},
+	}
+
+	operations := []struct {
+		name  string
+		apply func(a, b *CDFA) *CDFA
+		want  func(a, b bool) bool
+	}{
+		{"Intersect", (*CDFA).Intersect, func(a, b bool) bool { return a && b }},
+		{"Union", (*CDFA).Union, func(a, b bool) bool { return a || b }},
+		{"Difference", (*CDFA).Difference, func(a, b bool) bool { return a && !b }},
+		{"SymmetricDifference", (*CDFA).SymmetricDifference, func(a, b bool) bool { return a != b }},
+	}
+
+	for _, pair := range pairs {
+		a, b := CDFAfromDFA(mustDFA(t, pair.a)), CDFAfromDFA(mustDFA(t, pair.b))
+		abc := unionAlphabet(a.abc, b.abc)
+
+		for _, op := range operations {
+			res := op.apply(a, b)
+			if len(res.abc) != len(abc) {
+				t.Errorf("%v %v %v: alphabet %v, want %v", pair.a, op.name, pair.b, res.abc, string(abc))
+			}
+
+			for _, word := range words(abc, 5) {
+				if got, want := res.Accepts(word), op.want(a.Accepts(word), b.Accepts(word)); got != want {
+					t.Errorf("%v %v %v: %q accepted %v, want %v", pair.a, op.name, pair.b, word, got, want)
+				}
+			}
+		}
+
+		for _, cdfa := range []struct {
+			str  string
+			cdfa *CDFA
+		}{{pair.a, a}, {pair.b, b}} {
+			complement := cdfa.cdfa.Complement()
+			own := string(unionAlphabet(cdfa.cdfa.abc))
+
+			for _, word := range words(abc, 5) {
+				// complement is taken over alphabet of the automaton
+				want := !cdfa.cdfa.Accepts(word) && strings.Trim(word, own) == ""
+				if got := complement.Accepts(word); got != want {
+					t.Errorf("Complement %v: %q accepted %v, want %v", cdfa.str, word, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package formallang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// regExpNodeIntersect - words of all subexpressions, x & y
+type regExpNodeIntersect struct {
+	Next []regExpNode
+}
+
+// regExpNodeComplement - words over alphabet out of subexpression, ~x
+type regExpNodeComplement struct {
+	Next regExpNode
+}
+
+// minimalCDFA - compiles subexpression over alphabet to minimal CDFA
+func minimalCDFA(node regExpNode, abc map[rune]struct{}) *CDFA {
+	reg := &RegExp{abc: abc, tree: node}
+	return CDFAfromDFA(DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())).Minimise()
+}
+
+// cdfaToSubNFA - copies states of cdfa between begin and end, rejecting stock is skipped
+func cdfaToSubNFA(cdfa *CDFA, nfa *NFA, begin, end *nfanode) {
+	states := dfaNodesBFS(cdfa.start)
+	copies := make(map[*dfanode]*nfanode, len(states))
+	for _, state := range states {
+		if state == cdfa.stock && !state.endpoint {
+			continue
+		}
+
+		copies[state] = nfa.newNode()
+	}
+
+	for _, state := range states {
+		from, ok := copies[state]
+		if !ok {
+			continue
+		}
+
+		if state.endpoint {
+			from.linkEmpty(end)
+		}
+
+		for _, r := range state.sortedRunes() {
+			if to, ok := copies[state.next[r]]; ok {
+				from.link(r, to)
+			}
+		}
+	}
+
+	if start, ok := copies[cdfa.start]; ok {
+		begin.linkEmpty(start)
+	}
+}
+
+func (intersect regExpNodeIntersect) cdfa(abc map[rune]struct{}) *CDFA {
+	res := minimalCDFA(intersect.Next[0], abc)
+	for _, next := range intersect.Next[1:] {
+		res = res.Intersect(minimalCDFA(next, abc)).Minimise()
+	}
+
+	return res
+}
+
+func (complement regExpNodeComplement) cdfa(abc map[rune]struct{}) *CDFA {
+	return minimalCDFA(complement.Next, abc).Complement().Minimise()
+}
+
+// runesSet - converts sorted alphabet back to set
+func runesSet(abc []rune) map[rune]struct{} {
+	res := make(map[rune]struct{}, len(abc))
+	for _, r := range abc {
+		res[r] = struct{}{}
+	}
+
+	return res
+}
+
+func (regExpNodeIntersect) Priority() int { return intersectPriority }
+func (intersect regExpNodeIntersect) ToString(priority int, syntax Syntax) string {
+	prior := intersect.Priority()
+
+	var builder strings.Builder
+
+	if prior < priority {
+		builder.WriteRune('(')
+	}
+
+	builder.WriteString(intersect.Next[0].ToString(prior, syntax))
+
+	for _, next := range intersect.Next[1:] {
+		builder.WriteString(" & ")
+		builder.WriteString(next.ToString(prior, syntax))
+	}
+
+	if prior < priority {
+		builder.WriteRune(')')
+	}
+
+	return builder.String()
+}
+func (intersect regExpNodeIntersect) ToSubNFA(nfa *NFA, begin, end *nfanode) {
+	cdfaToSubNFA(intersect.cdfa(nfa.abc), nfa, begin, end)
+}
+
+func (regExpNodeComplement) Priority() int { return complementPriority }
+func (complement regExpNodeComplement) ToString(priority int, syntax Syntax) string {
+	prior := complement.Priority()
+
+	if prior < priority {
+		return fmt.Sprintf("(~%v)", complement.Next.ToString(prior, syntax))
+	}
+
+	return fmt.Sprintf("~%v", complement.Next.ToString(prior, syntax))
+}
+func (complement regExpNodeComplement) ToSubNFA(nfa *NFA, begin, end *nfanode) {
+	cdfaToSubNFA(complement.cdfa(nfa.abc), nfa, begin, end)
+}
+
+func (intersect regExpNodeIntersect) Optimize() regExpNode {
+	used := make(map[string]struct{})
+	nodes := make([]regExpNode, 0, len(intersect.Next))
+	for _, next := range intersect.Next {
+		next = next.Optimize()
+
+		inner := []regExpNode{next}
+		if nested, ok := next.(regExpNodeIntersect); ok {
+			inner = nested.Next
+		}
+
+		for _, next := range inner {
+			if _, ok := next.(regExpNodeEmptySet); ok {
+				return next
+			}
+
+			key := regExpNodeKey(next)
+			if _, ok := used[key]; ok {
+				continue
+			}
+
+			used[key] = struct{}{}
+			nodes = append(nodes, next)
+		}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	return regExpNodeIntersect{nodes}
+}
+
+func (complement regExpNodeComplement) Optimize() regExpNode {
+	next := complement.Next.Optimize()
+	if inner, ok := next.(regExpNodeComplement); ok {
+		return inner.Next
+	}
+
+	return regExpNodeComplement{next}
+}
+
+// Glushkov - positions are taken from equivalent expression without intersection, built by state elimination
+func (intersect regExpNodeIntersect) Glushkov(g *glushkovBuilder) glushkovSets {
+	return RegExpFromCDFA(intersect.cdfa(runesSet(g.abc))).tree.Glushkov(g)
+}
+
+// Glushkov - positions are taken from equivalent expression without complement, built by state elimination
+func (complement regExpNodeComplement) Glushkov(g *glushkovBuilder) glushkovSets {
+	return RegExpFromCDFA(complement.cdfa(runesSet(g.abc))).tree.Glushkov(g)
+}
+
+func (intersect regExpNodeIntersect) Nullable() bool {
+	for _, next := range intersect.Next {
+		if !next.Nullable() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (complement regExpNodeComplement) Nullable() bool { return !complement.Next.Nullable() }
+
+func (intersect regExpNodeIntersect) Derivative(by rune) regExpNode {
+	nodes := make([]regExpNode, len(intersect.Next))
+	for i, next := range intersect.Next {
+		nodes[i] = next.Derivative(by)
+	}
+
+	return aciNormalize(regExpNodeIntersect{nodes})
+}
+
+func (complement regExpNodeComplement) Derivative(by rune) regExpNode {
+	return aciNormalize(regExpNodeComplement{complement.Next.Derivative(by)})
+}
+
+// PartialDerivative - intersections of partial derivatives of all subexpressions
+func (intersect regExpNodeIntersect) PartialDerivative(by rune) regExpNodeSet {
+	products := [][]regExpNode{nil}
+	for _, next := range intersect.Next {
+		partial := next.PartialDerivative(by)
+
+		extended := make([][]regExpNode, 0, len(products)*len(partial))
+		for _, product := range products {
+			for _, node := range partial {
+				extended = append(extended, append(append([]regExpNode(nil), product...), node))
+			}
+		}
+
+		products = extended
+	}
+
+	res := regExpNodeSet{}
+	for _, product := range products {
+		res.add(regExpNodeIntersect{product})
+	}
+
+	return res
+}
+
+// PartialDerivative - complement is not linear, so whole derivative is single element
+func (complement regExpNodeComplement) PartialDerivative(by rune) regExpNodeSet {
+	nodes := make([]regExpNode, 0)
+	for _, node := range complement.Next.PartialDerivative(by) {
+		nodes = append(nodes, node)
+	}
+
+	res := regExpNodeSet{}
+	res.add(regExpNodeComplement{regExpNodeAdd{nodes}})
+	return res
+}
+
+// aciIntersect - canonical form of intersection, same as for alternation
+func aciIntersect(intersect regExpNodeIntersect) regExpNode {
+	used := make(map[string]regExpNode)
+	var collect func(node regExpNode) bool
+	collect = func(node regExpNode) bool {
+		switch node := node.(type) {
+		case regExpNodeIntersect:
+			for _, next := range node.Next {
+				if !collect(next) {
+					return false
+				}
+			}
+		case regExpNodeEmptySet:
+			return false
+		default:
+			used[regExpNodeKey(node)] = node
+		}
+
+		return true
+	}
+
+	for _, next := range intersect.Next {
+		if !collect(aciNormalize(next)) {
+			return regExpNodeEmptySet{}
+		}
+	}
+
+	keys := make([]string, 0, len(used))
+	for key := range used {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	nodes := make([]regExpNode, len(keys))
+	for i, key := range keys {
+		nodes[i] = used[key]
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	return regExpNodeIntersect{nodes}
+}
@@ -0,0 +1,40 @@
+package formallang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBooleanNodeContainsAbButNotBa(t *testing.T) {
+	const input = "~((a + b)*ba(a + b)*) & (a + b)*ab(a + b)*"
+	want := func(word string) bool {
+		return strings.Contains(word, "ab") && !strings.Contains(word, "ba")
+	}
+
+	reg := mustRegExp(t, input)
+	automata := []struct {
+		name    string
+		accepts func(string) bool
+	}{
+		{"minimal cdfa", CDFAfromDFA(DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())).Minimise().Accepts},
+		{"Matches", reg.Matches},
+		{"Antimirov NFA", AntimirovNFA(reg).Accepts},
+		{"Glushkov NFA", GlushkovNFA(reg).Accepts},
+		{"derivative DFA", DFAFromRegExpDerivatives(reg).Accepts},
+	}
+
+	for _, word := range words([]rune("ab"), 8) {
+		expected := want(word)
+		for _, a := range automata {
+			if got := a.accepts(word); got != expected {
+				t.Errorf("%q: %v accepts %v, want %v", word, a.name, got, expected)
+			}
+		}
+	}
+
+	str := reg.ToString()
+	if parsed := mustRegExp(t, str); !reflect.DeepEqual(reg, parsed) {
+		t.Errorf("%v parses to other expression %v", str, parsed.ToString())
+	}
+}
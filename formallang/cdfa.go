@@ -1,13 +1,7 @@
 package formallang
 
 import (
-	"fmt"
-	"log"
 	"maps"
-	"strings"
-
-	"github.com/goccy/go-graphviz"
-	"github.com/goccy/go-graphviz/cgraph"
 )
 
 // CDFA - imlement complete deterministic finite state automaton with
@@ -93,68 +87,91 @@ func DFAfromCDFA(cdfa *CDFA) *DFA {
 	return dfa
 }
 
-// Minimise constructs mdfa
+// Minimise constructs mdfa with Hopcroft's partition refinement
 func (cdfa CDFA) Minimise() *CDFA {
-	nodeClasses := make(map[*dfanode]string)
-	classesSet := make(map[string]int)
+	states := dfaNodesBFS(cdfa.start)
+	ids := make(map[*dfanode]int, len(states)+1)
+	for id, node := range states {
+		ids[node] = id
+	}
+	if _, ok := ids[cdfa.stock]; !ok && cdfa.stock != nil {
+		ids[cdfa.stock] = len(states)
+		states = append(states, cdfa.stock)
+	}
 
-	for node := range cdfa.nodes {
-		class := "0"
-		if node.endpoint {
-			class = "1"
+	alph := unionAlphabet(cdfa.abc)
+
+	// inverse[c][q] - states, that move to q by alph[c]
+	inverse := make([][][]int, len(alph))
+	for c, r := range alph {
+		inverse[c] = make([][]int, len(states))
+		for from, node := range states {
+			to := ids[cdfa.move(node, r)]
+			inverse[c][to] = append(inverse[c][to], from)
 		}
+	}
 
-		nodeClasses[node] = class
-		classesSet[class] = 1
+	classes := newPartition(len(states))
+	for _, node := range states {
+		if node.endpoint {
+			classes.mark(ids[node])
+		}
 	}
 
-	alph := make([]rune, 0, len(cdfa.abc))
-	for r := range cdfa.abc {
-		alph = append(alph, r)
+	inWork := make([][]bool, 0, len(states))
+	var tasks queue
+	addTask := func(block, c int) {
+		if !inWork[block][c] {
+			inWork[block][c] = true
+			tasks.Push([2]int{block, c})
+		}
 	}
 
-	for {
-		bufNodeClasses := make(map[*dfanode]string)
-		bufClassesSet := make(map[string]int)
-
-		cnt := 0
-		for from, fromclass := range nodeClasses {
-			newClassBuilder := &strings.Builder{}
-			
-			newClassBuilder.WriteString(fromclass)
-			for _, r := range alph {
-				to := from.next[r]
-				newClassBuilder.WriteRune(',')
-				newClassBuilder.WriteString(nodeClasses[to])
-			}
+	growWork := func() {
+		for len(inWork) < len(classes.first) {
+			inWork = append(inWork, make([]bool, len(alph)))
+		}
+	}
 
-			newClass := newClassBuilder.String()
+	classes.split(func(block, created int) {
+		growWork()
 
-			var classid int
-			if id, ok := bufClassesSet[newClass]; !ok {
-				bufClassesSet[newClass] = cnt
-				classid = cnt
-				cnt++
-			} else {
-				classid = id
-			}
+		smaller := created
+		if classes.size(block) < classes.size(created) {
+			smaller = block
+		}
 
-			bufNodeClasses[from] = fmt.Sprint(classid)
+		for c := range alph {
+			addTask(smaller, c)
 		}
+	})
+
+	for tasks.Size() > 0 {
+		task := tasks.Top().([2]int)
+		tasks.Pop()
+
+		splitter, c := task[0], task[1]
+		inWork[splitter][c] = false
 
-		if (len(bufClassesSet) == len(classesSet)) {
-			classesSet = bufClassesSet
-			nodeClasses = bufNodeClasses
-			break
+		for _, to := range append([]int{}, classes.block(splitter)...) {
+			for _, from := range inverse[c][to] {
+				classes.mark(from)
+			}
 		}
 
-		classesSet = bufClassesSet
-		nodeClasses = bufNodeClasses
-	}
+		classes.split(func(block, created int) {
+			growWork()
 
-	classesSet = make(map[string]int)
-	for _, class := range nodeClasses {
-		classesSet[class] = 1
+			for c := range alph {
+				if inWork[block][c] {
+					addTask(created, c)
+				} else if classes.size(created) < classes.size(block) {
+					addTask(created, c)
+				} else {
+					addTask(block, c)
+				}
+			}
+		})
 	}
 
 	mcdfa := &CDFA{
@@ -162,30 +179,38 @@ func (cdfa CDFA) Minimise() *CDFA {
 		nodes: make(map[*dfanode]struct{}),
 	}
 
-	classesToMCDFANodes := make(map[string]*dfanode)
-	for class := range classesSet {
+	classesToMCDFANodes := make([]*dfanode, len(classes.first))
+	for class := range classesToMCDFANodes {
 		classesToMCDFANodes[class] = mcdfa.newNode()
 	}
 
-	mcdfa.start = classesToMCDFANodes[nodeClasses[cdfa.start]]
-	mcdfa.stock = classesToMCDFANodes[nodeClasses[cdfa.stock]]
+	mcdfa.start = classesToMCDFANodes[classes.blockOf[ids[cdfa.start]]]
+	mcdfa.stock = classesToMCDFANodes[classes.blockOf[ids[cdfa.stock]]]
 
-	for cdfafrom, fromclass := range nodeClasses {
-		mcdfafrom := classesToMCDFANodes[fromclass]
-		
-		if cdfafrom.endpoint {
-			mcdfafrom.endpoint = true
+	for class, mcdfafrom := range classesToMCDFANodes {
+		cdfafrom := states[classes.block(class)[0]]
+		mcdfafrom.endpoint = cdfafrom.endpoint
+
+		for _, r := range alph {
+			cdfato := cdfa.move(cdfafrom, r)
+			mcdfafrom.link(r, classesToMCDFANodes[classes.blockOf[ids[cdfato]]])
 		}
+	}
 
-		for r, cdfato := range cdfafrom.next {
-			toclass := nodeClasses[cdfato]
-			mcdfato := classesToMCDFANodes[toclass]
+	return mcdfa
+}
 
-			mcdfafrom.link(r, mcdfato)
+// Accepts - checks if word belongs to language of CDFA
+func (cdfa *CDFA) Accepts(word string) bool {
+	curr := cdfa.start
+	for _, r := range word {
+		curr = curr.step(r)
+		if curr == nil {
+			return false
 		}
 	}
 
-	return mcdfa
+	return curr.accepting()
 }
 
 func (cdfa *CDFA) newNode() *dfanode {
@@ -200,83 +225,6 @@ func (cdfa *CDFA) newNode() *dfanode {
 }
 
 // Dump - dumps CDFA into png
-func (cdfa CDFA) Dump(filename string) {
-	g := graphviz.New()
-	graph, err := g.Graph(graphviz.StrictDirected)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func() {
-		if err := graph.Close(); err != nil {
-			log.Fatal(err)
-		}
-		g.Close()
-	}()
-
-	fromNFAtoGRAF := make(map[*dfanode]*cgraph.Node)
-	fromGRAFtoNFA := make(map[*cgraph.Node]*dfanode)
-	for nodeptr := range cdfa.nodes {
-		graphnode, err := graph.CreateNode(fmt.Sprintf("%p", nodeptr))
-
-		nodeShape := "circle"
-		if nodeptr.endpoint {
-			nodeShape = "doublecircle"
-		}
-
-		nodeLabel := ""
-		if nodeptr == cdfa.start {
-			nodeLabel = "in"
-		}
-		if nodeptr == cdfa.stock {
-			nodeLabel = "stok"
-		}
-
-		graphnode.SetLabel(nodeLabel).SetShape(cgraph.Shape(nodeShape))
-
-		fromGRAFtoNFA[graphnode] = nodeptr
-		fromNFAtoGRAF[nodeptr] = graphnode
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	buf := make(map[struct{ from, to *dfanode }]([]rune))
-
-	for from := range cdfa.nodes {
-		for r, to := range from.next {
-			buf[struct{ from, to *dfanode }{from, to}] = append(buf[struct{ from, to *dfanode }{from, to}], r)
-		}
-	}
-
-	for pair, runes := range buf {
-		edge, err := graph.CreateEdge(fmt.Sprintf("%p_%p", fromNFAtoGRAF[pair.from], fromNFAtoGRAF[pair.to]), fromNFAtoGRAF[pair.from], fromNFAtoGRAF[pair.to])
-
-		lable := fmt.Sprintf("%c", runes[0])
-		for _, r := range runes[1:] {
-			lable = fmt.Sprintf("%s,%c", lable, r)
-		}
-
-		edge.SetLabel(lable)
-
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	// 1. write encoded PNG data to buffer
-	//var buf bytes.Buffer
-	//if err := g.Render(graph, graphviz.PNG, &buf); err != nil {
-	//	log.Fatal(err)
-	//}
-	//
-	//// 2. get as image.Image instance
-	//image, err := g.RenderImage(graph)
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-	//
-	//// 3. write to file directly
-	if err := g.RenderFilename(graph, graphviz.PNG, filename); err != nil {
-		log.Fatal(err)
-	}
+func (cdfa CDFA) Dump(filename string) error {
+	return dump(&cdfa, filename, FormatPNG)
 }
@@ -0,0 +1,128 @@
+package formallang
+
+import (
+	"slices"
+	"strings"
+)
+
+// regExpNodeClass - any symbol of runes, if negated any symbol of alphabet except runes, . is negated empty class
+type regExpNodeClass struct {
+	runes   []rune
+	negated bool
+}
+
+func newRegExpNodeClass(runes []rune, negated bool) regExpNodeClass {
+	runes = slices.Clone(runes)
+	slices.Sort(runes)
+
+	return regExpNodeClass{slices.Compact(runes), negated}
+}
+
+func (class regExpNodeClass) contains(r rune) bool {
+	_, found := slices.BinarySearch(class.runes, r)
+	return found != class.negated
+}
+
+// symbols - symbols of alphabet matched by class, symbols out of alphabet are kept for positive class
+func (class regExpNodeClass) symbols(abc []rune) []rune {
+	if !class.negated {
+		return class.runes
+	}
+
+	res := make([]rune, 0, len(abc))
+	for _, r := range abc {
+		if class.contains(r) {
+			res = append(res, r)
+		}
+	}
+
+	return res
+}
+
+func writeClassRune(builder *strings.Builder, r rune) {
+	if isClassServiceRune(r, true) || r == escapeRune {
+		builder.WriteRune(escapeRune)
+	}
+	builder.WriteRune(r)
+}
+
+func (regExpNodeClass) Priority() int { return runePriority }
+func (class regExpNodeClass) ToString(int, Syntax) string {
+	if class.negated && len(class.runes) == 0 {
+		return "."
+	}
+
+	var builder strings.Builder
+
+	builder.WriteRune('[')
+	if class.negated {
+		builder.WriteRune('^')
+	}
+
+	for i := 0; i < len(class.runes); {
+		j := i
+		for j+1 < len(class.runes) && class.runes[j+1] == class.runes[j]+1 {
+			j++
+		}
+
+		if j-i >= 2 {
+			writeClassRune(&builder, class.runes[i])
+			builder.WriteRune('-')
+			writeClassRune(&builder, class.runes[j])
+		} else {
+			for _, r := range class.runes[i : j+1] {
+				writeClassRune(&builder, r)
+			}
+		}
+
+		i = j + 1
+	}
+
+	builder.WriteRune(']')
+
+	return builder.String()
+}
+func (class regExpNodeClass) ToSubNFA(nfa *NFA, begin, end *nfanode) {
+	for _, r := range class.symbols(unionAlphabet(nfa.abc)) {
+		begin.link(r, end)
+	}
+}
+
+func (class regExpNodeClass) Optimize() regExpNode {
+	if class.negated {
+		return class
+	}
+
+	switch len(class.runes) {
+	case 0:
+		return regExpNodeEmptySet{}
+	case 1:
+		return regExpNodeRune{class.runes[0]}
+	}
+
+	return class
+}
+
+func (class regExpNodeClass) Glushkov(g *glushkovBuilder) glushkovSets {
+	pos := g.addPosition(class.symbols(g.abc)...)
+	return glushkovSets{nullable: false, first: []int{pos}, last: []int{pos}}
+}
+
+func (regExpNodeClass) Nullable() bool { return false }
+
+func (class regExpNodeClass) Derivative(by rune) regExpNode {
+	if class.contains(by) {
+		return regExpNodeEmptyRune{}
+	}
+
+	return regExpNodeEmptySet{}
+}
+
+func (class regExpNodeClass) PartialDerivative(by rune) regExpNodeSet {
+	res := regExpNodeSet{}
+	if class.contains(by) {
+		res.add(regExpNodeEmptyRune{})
+	}
+
+	return res
+}
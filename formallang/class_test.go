@@ -0,0 +1,98 @@
+package formallang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassRangeAlphabet(t *testing.T) {
+	reg := mustRegExp(t, "[a-z]")
+	if len(reg.abc) != 26 {
+		t.Errorf("alphabet of [a-z] has %v symbols, want 26", len(reg.abc))
+	}
+	for r := 'a'; r <= 'z'; r++ {
+		if _, ok := reg.abc[r]; !ok {
+			t.Errorf("alphabet of [a-z] misses %q", r)
+		}
+	}
+}
+
+func TestClassRelativeToAlphabet(t *testing.T) {
+	tests := []struct {
+		str string
+		// accepted - all accepted words over a, b and c not longer than 4
+		accepted []string
+	}{
+		// b is out of alphabet
+		{"a[^a]", nil},
+		{"a.", []string{"aa"}},
+		{"[^a]a + b", []string{"b", "ba"}},
+		{"[^ab]c", []string{"cc"}},
+		{".b.", []string{"bbb"}},
+	}
+
+	for _, test := range tests {
+		dfa := mustDFA(t, test.str)
+
+		want := make(map[string]bool)
+		for _, word := range test.accepted {
+			want[word] = true
+		}
+
+		for _, word := range words([]rune("abc"), 4) {
+			if got := dfa.Accepts(word); got != want[word] {
+				t.Errorf("%v: %q: dfa accepts %v, want %v", test.str, word, got, want[word])
+			}
+		}
+	}
+}
+
+func TestClassParseErrors(t *testing.T) {
+	tests := []struct {
+		str, want string
+	}{
+		{"[z-a]", "can't parse on column 4"},
+		{"[ab", "can't parse on column 4"},
+		{"a[", "can't parse on column 3"},
+		{"[^", "can't parse on column 3"},
+	}
+
+	for _, test := range tests {
+		tokens, err := Tokenize(test.str)
+		if err != nil {
+			t.Fatalf("%q: %v", test.str, err)
+		}
+
+		_, err = RegExpFromTokens(tokens)
+		if err == nil {
+			t.Errorf("%q: expected error %q", test.str, test.want)
+			continue
+		}
+
+		if err.Error() != test.want {
+			t.Errorf("%q: got error %q, want %q", test.str, err, test.want)
+		}
+	}
+}
+
+func TestClassToStringRoundTrip(t *testing.T) {
+	inputs := []string{
+		"[a-z]",
+		"[^abc]d",
+		"[ab]*.",
+		"[cba]",
+		"[a-cx-z]",
+		"[^a-d]e",
+		`[\]\-\\]a`,
+	}
+
+	for _, input := range inputs {
+		reg := mustRegExp(t, input)
+
+		str := reg.ToString()
+		parsed := mustRegExp(t, str)
+		if !reflect.DeepEqual(reg, parsed) {
+			t.Errorf("%v: %v parses to other expression %v", input, str, parsed.ToString())
+		}
+	}
+}
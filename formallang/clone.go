@@ -0,0 +1,91 @@
+package formallang
+
+import "maps"
+
+// Clone - creates deep copy of NFA, nodes keep their creation order
+func (nfa *NFA) Clone() *NFA {
+	res := &NFA{
+		abc:     maps.Clone(nfa.abc),
+		nodes:   make(map[*nfanode]struct{}, len(nfa.nodes)),
+		created: nfa.created,
+	}
+
+	copies := make(map[*nfanode]*nfanode, len(nfa.nodes))
+	for node := range nfa.nodes {
+		copies[node] = &nfanode{
+			next:     make(map[rune]map[*nfanode]struct{}, len(node.next)),
+			empty:    make(map[*nfanode]struct{}, len(node.empty)),
+			linkscnt: node.linkscnt,
+			endpoint: node.endpoint,
+			order:    node.order,
+		}
+		res.nodes[copies[node]] = struct{}{}
+	}
+
+	for node, dup := range copies {
+		for r, links := range node.next {
+			dup.next[r] = make(map[*nfanode]struct{}, len(links))
+			for to := range links {
+				dup.next[r][copies[to]] = struct{}{}
+			}
+		}
+
+		for to := range node.empty {
+			dup.empty[copies[to]] = struct{}{}
+		}
+	}
+
+	res.start = copies[nfa.start]
+
+	return res
+}
+
+// WithoutEpsilon - creates NFA without empty transitions, nfa stays unchanged
+func (nfa *NFA) WithoutEpsilon() *NFA {
+	return nfa.Clone().RemoveEmpty()
+}
+
+// cloneDFANodes - copies nodes of deterministic automaton, returns copies by originals
+func cloneDFANodes(nodes map[*dfanode]struct{}) (map[*dfanode]struct{}, map[*dfanode]*dfanode) {
+	res := make(map[*dfanode]struct{}, len(nodes))
+	copies := make(map[*dfanode]*dfanode, len(nodes))
+	for node := range nodes {
+		copies[node] = &dfanode{
+			next:     make(map[rune]*dfanode, len(node.next)),
+			linkscnt: node.linkscnt,
+			endpoint: node.endpoint,
+		}
+		res[copies[node]] = struct{}{}
+	}
+
+	for node, dup := range copies {
+		for r, to := range node.next {
+			dup.next[r] = copies[to]
+		}
+	}
+
+	return res, copies
+}
+
+// Clone - creates deep copy of DFA
+func (dfa *DFA) Clone() *DFA {
+	nodes, copies := cloneDFANodes(dfa.nodes)
+
+	return &DFA{
+		abc:   maps.Clone(dfa.abc),
+		nodes: nodes,
+		start: copies[dfa.start],
+	}
+}
+
+// Clone - creates deep copy of CDFA
+func (cdfa *CDFA) Clone() *CDFA {
+	nodes, copies := cloneDFANodes(cdfa.nodes)
+
+	return &CDFA{
+		abc:   maps.Clone(cdfa.abc),
+		nodes: nodes,
+		start: copies[cdfa.start],
+		stock: copies[cdfa.stock],
+	}
+}
@@ -0,0 +1,101 @@
+package formallang
+
+import "testing"
+
+func TestWithoutEpsilonKeepsOriginal(t *testing.T) {
+	for dir, input := range stageInputs(t) {
+		reg := mustRegExp(t, input)
+		nfa := NFAFromRegExp(reg)
+
+		dot, text := dotString(t, nfa), textString(t, nfa)
+		res := nfa.WithoutEpsilon()
+
+		if got := dotString(t, nfa); got != dot {
+			t.Errorf("%v: WithoutEpsilon changed DOT of original\n%v\n%v", dir, dot, got)
+		}
+		if got := textString(t, nfa); got != text {
+			t.Errorf("%v: WithoutEpsilon changed text of original\n%v\n%v", dir, text, got)
+		}
+
+		if want, got := dotString(t, NFAFromRegExp(reg).RemoveEmpty()), dotString(t, res); want != got {
+			t.Errorf("%v: WithoutEpsilon differs from RemoveEmpty\n%v\n%v", dir, want, got)
+		}
+		for node := range res.nodes {
+			if _, ok := nfa.nodes[node]; ok {
+				t.Errorf("%v: WithoutEpsilon shares nodes with original", dir)
+				break
+			}
+		}
+	}
+}
+
+// spoilDFANodes - makes every state of copy accepting and removes its transitions
+func spoilDFANodes(nodes map[*dfanode]struct{}) {
+	for node := range nodes {
+		node.endpoint = true
+		clear(node.next)
+	}
+}
+
+func TestCloneDFA(t *testing.T) {
+	for dir, input := range stageInputs(t) {
+		dfa := DFAfromNFA(NFAFromRegExp(mustRegExp(t, input)).RemoveEmpty())
+		dot, text := dotString(t, dfa), textString(t, dfa)
+
+		clone := dfa.Clone()
+		if got := dotString(t, clone); got != dot {
+			t.Errorf("%v: clone differs\n%v\n%v", dir, dot, got)
+		}
+		for node := range clone.nodes {
+			if _, ok := dfa.nodes[node]; ok {
+				t.Errorf("%v: clone shares nodes with original", dir)
+				break
+			}
+		}
+
+		spoilDFANodes(clone.nodes)
+		if got := dotString(t, dfa); got != dot {
+			t.Errorf("%v: changing clone changed DOT of original\n%v\n%v", dir, dot, got)
+		}
+		if got := textString(t, dfa); got != text {
+			t.Errorf("%v: changing clone changed text of original\n%v\n%v", dir, text, got)
+		}
+	}
+
+	empty := DFAfromCDFA(emptyLanguage(t)).Clone()
+	if empty.start != nil || empty.Accepts("") {
+		t.Errorf("clone of empty dfa has start state")
+	}
+}
+
+func TestCloneCDFA(t *testing.T) {
+	for dir, input := range stageInputs(t) {
+		cdfa := CDFAfromDFA(DFAfromNFA(NFAFromRegExp(mustRegExp(t, input)).RemoveEmpty()))
+		dot, text := dotString(t, cdfa), textString(t, cdfa)
+
+		clone := cdfa.Clone()
+		if got := dotString(t, clone); got != dot {
+			t.Errorf("%v: clone differs\n%v\n%v", dir, dot, got)
+		}
+		if _, ok := clone.nodes[clone.stock]; !ok {
+			t.Errorf("%v: sink of clone is not its state", dir)
+		}
+		if want, got := dotString(t, cdfa.Minimise()), dotString(t, clone.Minimise()); want != got {
+			t.Errorf("%v: minimal clone differs\n%v\n%v", dir, want, got)
+		}
+		for node := range clone.nodes {
+			if _, ok := cdfa.nodes[node]; ok {
+				t.Errorf("%v: clone shares nodes with original", dir)
+				break
+			}
+		}
+
+		spoilDFANodes(clone.nodes)
+		if got := dotString(t, cdfa); got != dot {
+			t.Errorf("%v: changing clone changed DOT of original\n%v\n%v", dir, dot, got)
+		}
+		if got := textString(t, cdfa); got != text {
+			t.Errorf("%v: changing clone changed text of original\n%v\n%v", dir, text, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package formallang
+
+import "testing"
+
+// constructions - ways to build DFA of RegExp, that are checked against Thompson NFA
+var constructions = []struct {
+	name  string
+	build func(reg *RegExp) *DFA
+}{
+	{"Glushkov NFA", func(reg *RegExp) *DFA { return DFAfromNFA(GlushkovNFA(reg)) }},
+	{"derivative DFA", DFAFromRegExpDerivatives},
+	{"Antimirov NFA", func(reg *RegExp) *DFA { return DFAfromNFA(AntimirovNFA(reg)) }},
+}
+
+// featureInputs - expressions with extended operators, classes and boolean operators
+var featureInputs = []struct {
+	str    string
+	syntax Syntax
+}{
+	{"a?b+", SyntaxExtended},
+	{"(ab){2}|b{0}", SyntaxExtended},
+	{"a{2,}b", SyntaxExtended},
+	{"(a|b){1,3}c?", SyntaxExtended},
+	{"(a+b?)*|0", SyntaxExtended},
+	{"[ab]*c", SyntaxClassic},
+	{"[^a]b + a", SyntaxClassic},
+	{".a.", SyntaxClassic},
+	{"[a-c]+[^b-c]", SyntaxExtended},
+	{"~(a*)", SyntaxClassic},
+	{"a* & ~((aa)*)", SyntaxClassic},
+	{"~((a + b)*ba(a + b)*) & (a + b)*ab(a + b)*", SyntaxClassic},
+	{"~([ab]*) + c", SyntaxClassic},
+}
+
+// crossCheckRegExps - regExpInputs with n random expressions and featureInputs by source string
+func crossCheckRegExps(t *testing.T, n int) map[string]*RegExp {
+	t.Helper()
+
+	regs := make(map[string]*RegExp)
+	for _, input := range regExpInputs(t, n) {
+		regs[input] = mustRegExp(t, input)
+	}
+	for _, input := range featureInputs {
+		regs[input.str] = mustRegExpWithSyntax(t, input.str, input.syntax)
+	}
+
+	return regs
+}
+
+func TestConstructionsKeepLanguage(t *testing.T) {
+	for input, reg := range crossCheckRegExps(t, 300) {
+		thompson := minimalDFADOT(t, DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty()))
+
+		for _, construction := range constructions {
+			if got := minimalDFADOT(t, construction.build(reg)); got != thompson {
+				t.Errorf("%v: minimal CDFA of %v differs from Thompson\n%v\n%v", input, construction.name, thompson, got)
+			}
+		}
+	}
+}
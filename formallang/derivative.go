@@ -0,0 +1,231 @@
+package formallang
+
+import (
+	"maps"
+	"sort"
+)
+
+func (regExpNodeEmptyRune) Nullable() bool { return true }
+func (regExpNodeEmptySet) Nullable() bool  { return false }
+func (regExpNodeRune) Nullable() bool      { return false }
+func (regExpNodeClini) Nullable() bool     { return true }
+
+func (add regExpNodeAdd) Nullable() bool {
+	for _, next := range add.Next {
+		if next.Nullable() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (mul regExpNodeMul) Nullable() bool {
+	for _, next := range mul.Next {
+		if !next.Nullable() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (regExpNodeEmptyRune) Derivative(rune) regExpNode { return regExpNodeEmptySet{} }
+func (regExpNodeEmptySet) Derivative(rune) regExpNode  { return regExpNodeEmptySet{} }
+
+func (r regExpNodeRune) Derivative(by rune) regExpNode {
+	if r.r == by {
+		return regExpNodeEmptyRune{}
+	}
+
+	return regExpNodeEmptySet{}
+}
+
+func (add regExpNodeAdd) Derivative(by rune) regExpNode {
+	nodes := make([]regExpNode, len(add.Next))
+	for i, next := range add.Next {
+		nodes[i] = next.Derivative(by)
+	}
+
+	return aciNormalize(regExpNodeAdd{nodes})
+}
+
+func (mul regExpNodeMul) Derivative(by rune) regExpNode {
+	head, tail := mul.Next[0], mulOf(mul.Next[1:])
+
+	res := regExpNodeMul{[]regExpNode{head.Derivative(by), tail}}
+	if !head.Nullable() {
+		return aciNormalize(res)
+	}
+
+	return aciNormalize(regExpNodeAdd{[]regExpNode{res, tail.Derivative(by)}})
+}
+
+func (clini regExpNodeClini) Derivative(by rune) regExpNode {
+	return aciNormalize(regExpNodeMul{[]regExpNode{clini.Next.Derivative(by), clini}})
+}
+
+// aciNormalize - brings expression to canonical form modulo associativity, commutativity and idempotence of
+// alternation, associativity of concatenation and trivial rules for 1 and 0
+func aciNormalize(node regExpNode) regExpNode {
+	switch node := node.(type) {
+	case regExpNodeAdd:
+		used := make(map[string]regExpNode)
+		var collect func(node regExpNode)
+		collect = func(node regExpNode) {
+			switch node := node.(type) {
+			case regExpNodeAdd:
+				for _, next := range node.Next {
+					collect(next)
+				}
+			case regExpNodeEmptySet:
+			default:
+				used[regExpNodeKey(node)] = node
+			}
+		}
+
+		for _, next := range node.Next {
+			collect(aciNormalize(next))
+		}
+
+		keys := make([]string, 0, len(used))
+		for key := range used {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		nodes := make([]regExpNode, len(keys))
+		for i, key := range keys {
+			nodes[i] = used[key]
+		}
+
+		switch len(nodes) {
+		case 0:
+			return regExpNodeEmptySet{}
+		case 1:
+			return nodes[0]
+		}
+
+		return regExpNodeAdd{nodes}
+	case regExpNodeMul:
+		nodes := make([]regExpNode, 0, len(node.Next))
+		for _, next := range node.Next {
+			switch next := aciNormalize(next).(type) {
+			case regExpNodeEmptySet:
+				return next
+			case regExpNodeEmptyRune:
+			case regExpNodeMul:
+				nodes = append(nodes, next.Next...)
+			default:
+				nodes = append(nodes, next)
+			}
+		}
+
+		return mulOf(nodes)
+	case regExpNodeClini:
+		switch next := aciNormalize(node.Next).(type) {
+		case regExpNodeEmptySet, regExpNodeEmptyRune:
+			return regExpNodeEmptyRune{}
+		case regExpNodeClini:
+			return next
+		default:
+			return regExpNodeClini{next}
+		}
+	case regExpNodePlus:
+		return regExpNodePlus{aciNormalize(node.Next)}
+	case regExpNodeOptional:
+		return regExpNodeOptional{aciNormalize(node.Next)}
+	case regExpNodeRepeat:
+		return regExpNodeRepeat{aciNormalize(node.Next), node.min, node.max}
+	case regExpNodeIntersect:
+		return aciIntersect(node)
+	case regExpNodeComplement:
+		next := aciNormalize(node.Next)
+		if inner, ok := next.(regExpNodeComplement); ok {
+			return inner.Next
+		}
+
+		return regExpNodeComplement{next}
+	}
+
+	return node
+}
+
+// Nullable - checks if empty word belongs to language of RegExp
+func (reg RegExp) Nullable() bool {
+	return reg.tree.Nullable()
+}
+
+// Derivative - constructs Brzozowski derivative of RegExp by symbol
+func (reg RegExp) Derivative(r rune) *RegExp {
+	return &RegExp{
+		abc:    maps.Clone(reg.abc),
+		tree:   reg.tree.Derivative(r),
+		syntax: reg.syntax,
+	}
+}
+
+// Matches - checks if word belongs to language of RegExp without building automaton
+func (reg RegExp) Matches(word string) bool {
+	node := aciNormalize(reg.tree)
+	for _, r := range word {
+		if _, ok := reg.abc[r]; !ok {
+			return false
+		}
+
+		node = node.Derivative(r)
+
+		if _, ok := node.(regExpNodeEmptySet); ok {
+			return false
+		}
+	}
+
+	return node.Nullable()
+}
+
+// DFAFromRegExpDerivatives - constructs DFA which states are derivatives of RegExp
+func DFAFromRegExpDerivatives(reg *RegExp) *DFA {
+	dfa := &DFA{
+		abc:   maps.Clone(reg.abc),
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	alph := unionAlphabet(dfa.abc)
+
+	start := aciNormalize(reg.tree)
+	used := make(map[string]*dfanode)
+
+	var tasks queue
+	get := func(expr regExpNode) *dfanode {
+		key := regExpNodeKey(expr)
+		if node, ok := used[key]; ok {
+			return node
+		}
+
+		node := dfa.newNode()
+		node.endpoint = expr.Nullable()
+		used[key] = node
+
+		tasks.Push(expr)
+		return node
+	}
+
+	dfa.start = get(start)
+
+	for tasks.Size() > 0 {
+		expr := tasks.Top().(regExpNode)
+		tasks.Pop()
+
+		from := used[regExpNodeKey(expr)]
+		for _, r := range alph {
+			next := expr.Derivative(r)
+			if _, ok := next.(regExpNodeEmptySet); ok {
+				continue
+			}
+
+			from.link(r, get(next))
+		}
+	}
+
+	return dfa
+}
@@ -0,0 +1,15 @@
+package formallang
+
+import "testing"
+
+func TestMatchesAgreesWithNFA(t *testing.T) {
+	for input, reg := range crossCheckRegExps(t, 100) {
+		nfa := NFAFromRegExp(reg)
+
+		for _, word := range words([]rune("abc"), 5) {
+			if got, want := reg.Matches(word), nfa.Accepts(word); got != want {
+				t.Errorf("%v %q: Matches is %v, NFA accepts %v", input, word, got, want)
+			}
+		}
+	}
+}
@@ -1,15 +1,9 @@
 package formallang
 
 import (
-	"fmt"
-	"log"
+	"encoding/binary"
 	"maps"
 	"sort"
-	"strings"
-	"unsafe"
-
-	"github.com/goccy/go-graphviz"
-	"github.com/goccy/go-graphviz/cgraph"
 )
 
 // DFA - imlement deterministic finite automaton
@@ -26,6 +20,10 @@ type dfanode struct {
 }
 
 func (from *dfanode) link(r rune, to *dfanode) *dfanode {
+	if prev, ok := from.next[r]; ok {
+		from.unlink(r, prev)
+	}
+
 	from.next[r] = to
 
 	if from != to {
@@ -36,6 +34,10 @@ func (from *dfanode) link(r rune, to *dfanode) *dfanode {
 }
 
 func (from *dfanode) unlink(r rune, to *dfanode) *dfanode {
+	if from.next[r] != to {
+		return from
+	}
+
 	if from != to {
 		to.linkscnt--
 	}
@@ -44,6 +46,55 @@ func (from *dfanode) unlink(r rune, to *dfanode) *dfanode {
 	return from
 }
 
+// step - makes transition, nil node is implicit stock
+func (from *dfanode) step(r rune) *dfanode {
+	if from == nil {
+		return nil
+	}
+
+	return from.next[r]
+}
+
+func (from *dfanode) accepting() bool {
+	return from != nil && from.endpoint
+}
+
+// sortedRunes - returns keys of transition map in increasing order
+func (from *dfanode) sortedRunes() []rune {
+	res := make([]rune, 0, len(from.next))
+	for r := range from.next {
+		res = append(res, r)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// dfaNodesBFS - returns nodes reachable from start in breadth first order
+func dfaNodesBFS(start *dfanode) []*dfanode {
+	if start == nil {
+		return nil
+	}
+
+	res := []*dfanode{start}
+	used := map[*dfanode]struct{}{start: {}}
+
+	for i := 0; i < len(res); i++ {
+		from := res[i]
+		for _, r := range from.sortedRunes() {
+			to := from.next[r]
+			if _, ok := used[to]; ok {
+				continue
+			}
+
+			used[to] = struct{}{}
+			res = append(res, to)
+		}
+	}
+
+	return res
+}
+
 func (dfa *DFA) newNode() *dfanode {
 	res := dfanode{
 		next:     make(map[rune]*dfanode),
@@ -62,87 +113,73 @@ func DFAfromNFA(nfa *NFA) *DFA {
 		nodes: make(map[*dfanode]struct{}),
 	}
 
-	SliceToString := func(sl []*nfanode) string {
-		builder := &strings.Builder{}
-
-		sort.Slice(sl, func(i, j int) bool {
-			return fmt.Sprint(sl[i]) < fmt.Sprint(sl[j])
-		})
-
-		fmt.Fprintf(builder, "%v", len(sl))
+	nfanodes, ids := nfa.numerate()
+	alph := unionAlphabet(dfa.abc)
 
-		for _, ptr := range sl {
-			fmt.Fprintf(builder, ",%p", ptr)
+	// next[id][c] - sorted ids of nodes reachable from nfanodes[id] by alph[c]
+	next := make([][][]int, len(nfanodes))
+	for id, from := range nfanodes {
+		next[id] = make([][]int, len(alph))
+		for c, r := range alph {
+			for to := range from.next[r] {
+				next[id][c] = append(next[id][c], ids[to])
+			}
 		}
-
-		return builder.String()
 	}
-	StringToSlice := func(str string) []*nfanode {
-		reader := strings.NewReader(str)
-		size := 0
-		fmt.Fscanf(reader, "%d", &size)
-		res := make([]*nfanode, size)
-
-		for i := 0; i < size; i++ {
-			var ptr uintptr
-			fmt.Fscanf(reader, ",%v", &ptr)
-			res[i] = (*nfanode)(unsafe.Pointer(ptr))
-		}
 
-		return res
-	}
-
-	condition := append([]*nfanode{}, nfa.start)
+	condition := []int{ids[nfa.start]}
 
 	var tasks queue
 	used := make(map[string]*dfanode)
 
 	dfa.start = dfa.newNode()
 	dfa.start.endpoint = nfa.start.endpoint
-	tasks.Push(SliceToString(condition))
-	used[SliceToString(condition)] = dfa.start
+	tasks.Push(condition)
+	used[subsetKey(condition)] = dfa.start
 
+	inCond := make([]bool, len(nfanodes))
 	for tasks.Size() > 0 {
-		currCondString := tasks.Top().(string)
+		currCond := tasks.Top().([]int)
 		tasks.Pop()
 
-		currCond := StringToSlice(currCondString)
-		dfafrom := used[currCondString]
+		dfafrom := used[subsetKey(currCond)]
 
-		for r := range dfa.abc {
-			nextCondSet := make(map[*nfanode]struct{})
+		for c, r := range alph {
+			nextCond := make([]int, 0)
 			endpoint := false
 
 			for _, nfafrom := range currCond {
-				for nfato := range nfafrom.next[r] {
-					if nfato.endpoint {
-						endpoint = true
+				for _, nfato := range next[nfafrom][c] {
+					if inCond[nfato] {
+						continue
 					}
 
-					nextCondSet[nfato] = struct{}{}
+					inCond[nfato] = true
+					endpoint = endpoint || nfanodes[nfato].endpoint
+					nextCond = append(nextCond, nfato)
 				}
 			}
 
-			if len(nextCondSet) == 0 {
-				continue
+			for _, id := range nextCond {
+				inCond[id] = false
 			}
 
-			nextCond := make([]*nfanode, 0, len(nextCondSet))
-			for key := range nextCondSet {
-				nextCond = append(nextCond, key)
+			if len(nextCond) == 0 {
+				continue
 			}
 
-			nextCondString := SliceToString(nextCond)
-			if _, ok := used[nextCondString]; !ok {
+			sort.Ints(nextCond)
 
+			nextCondKey := subsetKey(nextCond)
+			if _, ok := used[nextCondKey]; !ok {
 				node := dfa.newNode()
 				node.endpoint = endpoint
-				used[nextCondString] = node
+				used[nextCondKey] = node
 
-				tasks.Push(nextCondString)
+				tasks.Push(nextCond)
 			}
 
-			dfato := used[nextCondString]
+			dfato := used[nextCondKey]
 
 			dfafrom.link(r, dfato)
 		}
@@ -151,81 +188,30 @@ func DFAfromNFA(nfa *NFA) *DFA {
 	return dfa
 }
 
-// Dump - dumps DFA into png
-func (dfa DFA) Dump(filename string) {
-	g := graphviz.New()
-	graph, err := g.Graph(graphviz.StrictDirected)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func() {
-		if err := graph.Close(); err != nil {
-			log.Fatal(err)
-		}
-		g.Close()
-	}()
-
-	fromNFAtoGRAF := make(map[*dfanode]*cgraph.Node)
-	fromGRAFtoNFA := make(map[*cgraph.Node]*dfanode)
-	for nodeptr := range dfa.nodes {
-		graphnode, err := graph.CreateNode(fmt.Sprintf("%p", nodeptr))
-
-		nodeShape := "circle"
-		if nodeptr.endpoint {
-			nodeShape = "doublecircle"
-		}
-
-		nodeLabel := ""
-		if nodeptr == dfa.start {
-			nodeLabel = "in"
-		}
-
-		graphnode.SetLabel(nodeLabel).SetShape(cgraph.Shape(nodeShape))
-
-		fromGRAFtoNFA[graphnode] = nodeptr
-		fromNFAtoGRAF[nodeptr] = graphnode
-		if err != nil {
-			log.Fatal(err)
-		}
+// subsetKey - encodes sorted ids of nfa nodes into map key
+func subsetKey(ids []int) string {
+	buf := make([]byte, 0, 2*len(ids))
+	for _, id := range ids {
+		buf = binary.AppendUvarint(buf, uint64(id))
 	}
 
-	buf := make(map[struct{ from, to *dfanode }]([]rune))
+	return string(buf)
+}
 
-	for from := range dfa.nodes {
-		for r, to := range from.next {
-			buf[struct{ from, to *dfanode }{from, to}] = append(buf[struct{ from, to *dfanode }{from, to}], r)
+// Accepts - checks if word belongs to language of DFA
+func (dfa *DFA) Accepts(word string) bool {
+	curr := dfa.start
+	for _, r := range word {
+		curr = curr.step(r)
+		if curr == nil {
+			return false
 		}
 	}
 
-	for pair, runes := range buf {
-		edge, err := graph.CreateEdge(fmt.Sprintf("%p_%p", fromNFAtoGRAF[pair.from], fromNFAtoGRAF[pair.to]), fromNFAtoGRAF[pair.from], fromNFAtoGRAF[pair.to])
-
-		lable := fmt.Sprintf("%c", runes[0])
-		for _, r := range runes[1:] {
-			lable = fmt.Sprintf("%s,%c", lable, r)
-		}
-
-		edge.SetLabel(lable)
-
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
+	return curr.accepting()
+}
 
-	// 1. write encoded PNG data to buffer
-	//var buf bytes.Buffer
-	//if err := g.Render(graph, graphviz.PNG, &buf); err != nil {
-	//	log.Fatal(err)
-	//}
-	//
-	//// 2. get as image.Image instance
-	//image, err := g.RenderImage(graph)
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-	//
-	//// 3. write to file directly
-	if err := g.RenderFilename(graph, graphviz.PNG, filename); err != nil {
-		log.Fatal(err)
-	}
+// Dump - dumps DFA into png
+func (dfa DFA) Dump(filename string) error {
+	return dump(&dfa, filename, FormatPNG)
 }
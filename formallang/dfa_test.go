@@ -0,0 +1,131 @@
+package formallang
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// pointerSubsetDFA - subset construction keyed by strings of node pointers, as DFAfromNFA was built before
+// dense ids, subsets are kept by their keys instead of scanning pointers back
+func pointerSubsetDFA(nfa *NFA) *DFA {
+	dfa := &DFA{
+		abc:   maps.Clone(nfa.abc),
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	sliceToString := func(sl []*nfanode) string {
+		builder := &strings.Builder{}
+
+		sort.Slice(sl, func(i, j int) bool {
+			return fmt.Sprint(sl[i]) < fmt.Sprint(sl[j])
+		})
+
+		fmt.Fprintf(builder, "%v", len(sl))
+
+		for _, ptr := range sl {
+			fmt.Fprintf(builder, ",%p", ptr)
+		}
+
+		return builder.String()
+	}
+
+	condition := []*nfanode{nfa.start}
+	conditions := make(map[string][]*nfanode)
+
+	var tasks queue
+	used := make(map[string]*dfanode)
+
+	dfa.start = dfa.newNode()
+	dfa.start.endpoint = nfa.start.endpoint
+	tasks.Push(sliceToString(condition))
+	used[sliceToString(condition)] = dfa.start
+	conditions[sliceToString(condition)] = condition
+
+	for tasks.Size() > 0 {
+		currCondString := tasks.Top().(string)
+		tasks.Pop()
+
+		currCond := conditions[currCondString]
+		dfafrom := used[currCondString]
+
+		for r := range dfa.abc {
+			nextCondSet := make(map[*nfanode]struct{})
+			endpoint := false
+
+			for _, nfafrom := range currCond {
+				for nfato := range nfafrom.next[r] {
+					if nfato.endpoint {
+						endpoint = true
+					}
+
+					nextCondSet[nfato] = struct{}{}
+				}
+			}
+
+			if len(nextCondSet) == 0 {
+				continue
+			}
+
+			nextCond := make([]*nfanode, 0, len(nextCondSet))
+			for key := range nextCondSet {
+				nextCond = append(nextCond, key)
+			}
+
+			nextCondString := sliceToString(nextCond)
+			if _, ok := used[nextCondString]; !ok {
+				node := dfa.newNode()
+				node.endpoint = endpoint
+				used[nextCondString] = node
+				conditions[nextCondString] = nextCond
+
+				tasks.Push(nextCondString)
+			}
+
+			dfafrom.link(r, used[nextCondString])
+		}
+	}
+
+	return dfa
+}
+
+func TestDFAfromNFAMatchesPointerSubsets(t *testing.T) {
+	for _, input := range regExpInputs(t, 300) {
+		nfa := NFAFromRegExp(mustRegExp(t, input)).RemoveEmpty()
+
+		dense, pointers := minimalDFADOT(t, DFAfromNFA(nfa)), minimalDFADOT(t, pointerSubsetDFA(nfa))
+		if dense != pointers {
+			t.Errorf("%v: minimal CDFA differs from pointer subset construction\n%v\n%v", input, pointers, dense)
+		}
+	}
+}
+
+func benchmarkDFAfromNFA(b *testing.B, build func(nfa *NFA) *DFA) {
+	inputs := stageInputs(b)
+
+	for _, name := range []string{"test7", "test8"} {
+		input, ok := inputs[filepath.Join(stagesDir, name)]
+		if !ok {
+			b.Fatalf("no input for %v", name)
+		}
+
+		nfa := NFAFromRegExp(mustRegExp(b, input)).RemoveEmpty()
+
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				build(nfa)
+			}
+		})
+	}
+}
+
+func BenchmarkDFAfromNFA(b *testing.B) {
+	benchmarkDFAfromNFA(b, DFAfromNFA)
+}
+
+func BenchmarkDFAfromNFAPointerSubsets(b *testing.B) {
+	benchmarkDFAfromNFA(b, pointerSubsetDFA)
+}
@@ -0,0 +1,189 @@
+package formallang
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type dotState struct {
+	name     string
+	start    bool
+	endpoint bool
+	stock    bool
+}
+
+// dotEdge - transition by runes, empty marks empty transition of NFA
+type dotEdge struct {
+	from, to int
+	runes    []rune
+	empty    bool
+}
+
+func dotQuote(str string) string {
+	str = strings.ReplaceAll(str, `\`, `\\`)
+	str = strings.ReplaceAll(str, `"`, `\"`)
+	return `"` + str + `"`
+}
+
+func dotLabel(runes []rune, empty bool) string {
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	labels := make([]string, 0, len(runes)+1)
+	if empty {
+		labels = append(labels, emptySymbol)
+	}
+	for _, r := range runes {
+		if string(r) == emptySymbol {
+			labels = append(labels, `\`+emptySymbol)
+			continue
+		}
+		labels = append(labels, string(r))
+	}
+
+	return strings.Join(labels, ",")
+}
+
+// writeDOT - writes graph in graphviz DOT language, edges with same ends are merged
+func writeDOT(w io.Writer, states []dotState, edges []dotEdge) error {
+	merged := make(map[[2]int]dotEdge)
+	for _, edge := range edges {
+		key := [2]int{edge.from, edge.to}
+		curr := merged[key]
+		curr.runes = append(curr.runes, edge.runes...)
+		curr.empty = curr.empty || edge.empty
+		merged[key] = curr
+	}
+
+	keys := make([][2]int, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	builder := &strings.Builder{}
+	builder.WriteString("digraph {\n")
+	builder.WriteString("\trankdir=LR;\n")
+	builder.WriteString("\tin [shape=point];\n")
+
+	for _, state := range states {
+		shape := "circle"
+		if state.endpoint {
+			shape = "doublecircle"
+		}
+
+		style := ""
+		if state.stock {
+			style = ", style=dashed"
+		}
+
+		fmt.Fprintf(builder, "\t%s [shape=%s%s];\n", state.name, shape, style)
+	}
+
+	for _, state := range states {
+		if state.start {
+			fmt.Fprintf(builder, "\tin -> %s;\n", state.name)
+		}
+	}
+
+	for _, key := range keys {
+		fmt.Fprintf(builder, "\t%s -> %s [label=%s];\n", states[key[0]].name, states[key[1]].name, dotQuote(dotLabel(merged[key].runes, merged[key].empty)))
+	}
+
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// dfaDOT - collects states and edges of dfanode graph, names are given in breadth first order
+func dfaDOT(start, stock *dfanode) ([]dotState, []dotEdge) {
+	nodes := dfaNodesBFS(start)
+	ids := make(map[*dfanode]int, len(nodes))
+	for id, node := range nodes {
+		ids[node] = id
+	}
+	if _, ok := ids[stock]; !ok && stock != nil {
+		ids[stock] = len(nodes)
+		nodes = append(nodes, stock)
+	}
+
+	states := make([]dotState, len(nodes))
+	edges := make([]dotEdge, 0)
+	for id, node := range nodes {
+		states[id] = dotState{
+			name:     fmt.Sprintf("q%v", id),
+			start:    node == start,
+			endpoint: node.endpoint,
+			stock:    node == stock,
+		}
+
+		for _, r := range node.sortedRunes() {
+			edges = append(edges, dotEdge{id, ids[node.next[r]], []rune{r}, false})
+		}
+	}
+
+	return states, edges
+}
+
+// dot - collects states and edges of NFA, names are given in breadth first order
+func (nfa *NFA) dot() ([]dotState, []dotEdge) {
+	nodes, ids := nfa.numerate()
+
+	states := make([]dotState, len(nodes))
+	edges := make([]dotEdge, 0)
+	for id, node := range nodes {
+		states[id] = dotState{
+			name:     fmt.Sprintf("q%v", id),
+			start:    node == nfa.start,
+			endpoint: node.endpoint,
+		}
+
+		from := len(edges)
+		for to := range node.empty {
+			edges = append(edges, dotEdge{id, ids[to], nil, true})
+		}
+		for r, links := range node.next {
+			for to := range links {
+				edges = append(edges, dotEdge{id, ids[to], []rune{r}, false})
+			}
+		}
+
+		added := edges[from:]
+		sort.Slice(added, func(i, j int) bool {
+			if added[i].empty != added[j].empty {
+				return added[i].empty
+			}
+			if !added[i].empty && added[i].runes[0] != added[j].runes[0] {
+				return added[i].runes[0] < added[j].runes[0]
+			}
+			return added[i].to < added[j].to
+		})
+	}
+
+	return states, edges
+}
+
+// WriteDOT - writes NFA in graphviz DOT language
+func (nfa *NFA) WriteDOT(w io.Writer) error {
+	states, edges := nfa.dot()
+	return writeDOT(w, states, edges)
+}
+
+// WriteDOT - writes DFA in graphviz DOT language
+func (dfa *DFA) WriteDOT(w io.Writer) error {
+	states, edges := dfaDOT(dfa.start, nil)
+	return writeDOT(w, states, edges)
+}
+
+// WriteDOT - writes CDFA in graphviz DOT language
+func (cdfa *CDFA) WriteDOT(w io.Writer) error {
+	states, edges := dfaDOT(cdfa.start, cdfa.stock)
+	return writeDOT(w, states, edges)
+}
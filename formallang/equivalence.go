@@ -0,0 +1,76 @@
+package formallang
+
+import (
+	"sort"
+	"strings"
+)
+
+type dfanodePair struct {
+	a, b *dfanode
+}
+
+// unionAlphabet - returns sorted union of alphabets
+func unionAlphabet(abcs ...map[rune]struct{}) []rune {
+	union := make(map[rune]struct{})
+	for _, abc := range abcs {
+		for r := range abc {
+			union[r] = struct{}{}
+		}
+	}
+
+	res := make([]rune, 0, len(union))
+	for r := range union {
+		res = append(res, r)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// Equivalent - checks if DFAs describe the same language, otherwise returns shortest distinguishing word
+func Equivalent(a, b *DFA) (bool, string) {
+	abc := unionAlphabet(a.abc, b.abc)
+
+	type parent struct {
+		pair dfanodePair
+		r    rune
+	}
+
+	start := dfanodePair{a.start, b.start}
+	parents := map[dfanodePair]parent{start: {}}
+
+	var tasks queue
+	tasks.Push(start)
+
+	for tasks.Size() > 0 {
+		curr := tasks.Top().(dfanodePair)
+		tasks.Pop()
+
+		if curr.a.accepting() != curr.b.accepting() {
+			word := make([]rune, 0)
+			for curr != start {
+				word = append(word, parents[curr].r)
+				curr = parents[curr].pair
+			}
+
+			builder := &strings.Builder{}
+			for i := len(word) - 1; i >= 0; i-- {
+				builder.WriteRune(word[i])
+			}
+
+			return false, builder.String()
+		}
+
+		for _, r := range abc {
+			next := dfanodePair{curr.a.step(r), curr.b.step(r)}
+			if _, ok := parents[next]; ok {
+				continue
+			}
+
+			parents[next] = parent{curr, r}
+			tasks.Push(next)
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,40 @@
+package formallang
+
+import "testing"
+
+func TestEquivalent(t *testing.T) {
+	empty := DFAfromCDFA(emptyLanguage(t))
+
+	tests := []struct {
+		name       string
+		a, b       *DFA
+		equivalent bool
+		word       string
+	}{
+		{"a* vs aa*", mustDFA(t, "a*"), mustDFA(t, "aa*"), false, ""},
+		{"(a + b)* vs a*", mustDFA(t, "(a + b)*"), mustDFA(t, "a*"), false, "b"},
+		{"(a + b)* vs (a*b*)*", mustDFA(t, "(a + b)*"), mustDFA(t, "(a*b*)*"), true, ""},
+		{"ab + ba vs ba + ab", mustDFA(t, "ab + ba"), mustDFA(t, "ba + ab"), true, ""},
+		{"a(a + b)b vs a(a + b)a", mustDFA(t, "a(a + b)b"), mustDFA(t, "a(a + b)a"), false, "aaa"},
+		// alphabets differ
+		{"a* vs b*", mustDFA(t, "a*"), mustDFA(t, "b*"), false, "a"},
+		{"a*b vs b", mustDFA(t, "a*b"), mustDFA(t, "b"), false, "ab"},
+		// no start state
+		{"empty vs empty", empty, DFAfromCDFA(emptyLanguage(t)), true, ""},
+		{"empty vs aa", empty, mustDFA(t, "aa"), false, "aa"},
+		{"1 vs empty", mustDFA(t, "1"), empty, false, ""},
+	}
+
+	for _, test := range tests {
+		equivalent, word := Equivalent(test.a, test.b)
+		if equivalent != test.equivalent || word != test.word {
+			t.Errorf("%v: got %v %q, want %v %q", test.name, equivalent, word, test.equivalent, test.word)
+		}
+
+		// counterexample doesn't depend on order of arguments
+		equivalent, word = Equivalent(test.b, test.a)
+		if equivalent != test.equivalent || word != test.word {
+			t.Errorf("%v swapped: got %v %q, want %v %q", test.name, equivalent, word, test.equivalent, test.word)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package formallang
+
+import (
+	"fmt"
+	"maps"
+)
+
+// regExpNodePlus - one or more repetitions, x+
+type regExpNodePlus struct {
+	Next regExpNode
+}
+
+func (regExpNodePlus) Priority() int { return cliniPriority }
+func (plus regExpNodePlus) ToString(priority int, syntax Syntax) string {
+	if syntax != SyntaxExtended {
+		return regExpNodeMul{[]regExpNode{plus.Next, regExpNodeClini{plus.Next}}}.ToString(priority, syntax)
+	}
+
+	return fmt.Sprintf("%v+", plus.Next.ToString(plus.Priority(), syntax))
+}
+func (plus regExpNodePlus) ToSubNFA(nfa *NFA, begin, end *nfanode) {
+	loopBegin, loopEnd := nfa.newNode(), nfa.newNode()
+	plus.Next.ToSubNFA(nfa, loopBegin, loopEnd)
+	begin.linkEmpty(loopBegin)
+	loopEnd.linkEmpty(loopBegin)
+	loopEnd.linkEmpty(end)
+}
+
+// regExpNodeOptional - zero or one occurrence, x?
+type regExpNodeOptional struct {
+	Next regExpNode
+}
+
+func (regExpNodeOptional) Priority() int { return cliniPriority }
+func (optional regExpNodeOptional) ToString(priority int, syntax Syntax) string {
+	if syntax != SyntaxExtended {
+		return regExpNodeAdd{[]regExpNode{optional.Next, regExpNodeEmptyRune{}}}.ToString(priority, syntax)
+	}
+
+	return fmt.Sprintf("%v?", optional.Next.ToString(optional.Priority(), syntax))
+}
+func (optional regExpNodeOptional) ToSubNFA(nfa *NFA, begin, end *nfanode) {
+	optional.Next.ToSubNFA(nfa, begin, end)
+	begin.linkEmpty(end)
+}
+
+// regExpNodeRepeat - from min to max repetitions, max is -1 if unbounded, x{min,max}
+type regExpNodeRepeat struct {
+	Next     regExpNode
+	min, max int
+}
+
+// unfold - expresses repetition with concatenation, optional and star
+func (repeat regExpNodeRepeat) unfold() regExpNode {
+	nodes := make([]regExpNode, 0, max(repeat.min, repeat.max)+1)
+	for i := 0; i < repeat.min; i++ {
+		nodes = append(nodes, repeat.Next)
+	}
+
+	if repeat.max == -1 {
+		nodes = append(nodes, regExpNodeClini{repeat.Next})
+	}
+	for i := repeat.min; i < repeat.max; i++ {
+		nodes = append(nodes, regExpNodeOptional{repeat.Next})
+	}
+
+	return mulOf(nodes)
+}
+
+// rest - repetition that remains after first occurrence
+func (repeat regExpNodeRepeat) rest() regExpNodeRepeat {
+	res := regExpNodeRepeat{repeat.Next, max(repeat.min-1, 0), repeat.max}
+	if res.max > 0 {
+		res.max--
+	}
+
+	return res
+}
+
+func (regExpNodeRepeat) Priority() int { return cliniPriority }
+func (repeat regExpNodeRepeat) ToString(priority int, syntax Syntax) string {
+	if syntax != SyntaxExtended {
+		return repeat.unfold().ToString(priority, syntax)
+	}
+
+	next := repeat.Next.ToString(repeat.Priority(), syntax)
+	switch {
+	case repeat.max == -1:
+		return fmt.Sprintf("%v{%v,}", next, repeat.min)
+	case repeat.max == repeat.min:
+		return fmt.Sprintf("%v{%v}", next, repeat.min)
+	}
+
+	return fmt.Sprintf("%v{%v,%v}", next, repeat.min, repeat.max)
+}
+func (repeat regExpNodeRepeat) ToSubNFA(nfa *NFA, begin, end *nfanode) {
+	curr := begin
+	for i := 0; i < repeat.min; i++ {
+		next := nfa.newNode()
+		repeat.Next.ToSubNFA(nfa, curr, next)
+		curr = next
+	}
+
+	if repeat.max == -1 {
+		regExpNodeClini{repeat.Next}.ToSubNFA(nfa, curr, end)
+		return
+	}
+
+	for i := repeat.min; i < repeat.max; i++ {
+		next := nfa.newNode()
+		repeat.Next.ToSubNFA(nfa, curr, next)
+		curr.linkEmpty(end)
+		curr = next
+	}
+
+	curr.linkEmpty(end)
+}
+
+func (plus regExpNodePlus) Optimize() regExpNode {
+	switch next := plus.Next.Optimize().(type) {
+	case regExpNodeEmptySet, regExpNodeEmptyRune, regExpNodeClini, regExpNodePlus:
+		return next
+	case regExpNodeOptional:
+		return regExpNodeClini{next.Next}.Optimize()
+	default:
+		return regExpNodePlus{next}
+	}
+}
+
+func (optional regExpNodeOptional) Optimize() regExpNode {
+	next := optional.Next.Optimize()
+	if _, ok := next.(regExpNodeEmptySet); ok {
+		return regExpNodeEmptyRune{}
+	}
+	if next.Nullable() {
+		return next
+	}
+
+	return regExpNodeOptional{next}
+}
+
+func (repeat regExpNodeRepeat) Optimize() regExpNode {
+	next := repeat.Next.Optimize()
+
+	switch [2]int{repeat.min, repeat.max} {
+	case [2]int{0, 0}:
+		return regExpNodeEmptyRune{}
+	case [2]int{1, 1}:
+		return next
+	case [2]int{0, 1}:
+		return regExpNodeOptional{next}.Optimize()
+	case [2]int{0, -1}:
+		return regExpNodeClini{next}.Optimize()
+	case [2]int{1, -1}:
+		return regExpNodePlus{next}.Optimize()
+	}
+
+	switch next.(type) {
+	case regExpNodeEmptySet:
+		if repeat.min == 0 {
+			return regExpNodeEmptyRune{}
+		}
+		return next
+	case regExpNodeEmptyRune:
+		return next
+	}
+
+	return regExpNodeRepeat{next, repeat.min, repeat.max}
+}
+
+func (plus regExpNodePlus) Glushkov(g *glushkovBuilder) glushkovSets {
+	sets := plus.Next.Glushkov(g)
+	g.link(sets.last, sets.first)
+	return sets
+}
+
+func (optional regExpNodeOptional) Glushkov(g *glushkovBuilder) glushkovSets {
+	sets := optional.Next.Glushkov(g)
+	sets.nullable = true
+	return sets
+}
+
+func (repeat regExpNodeRepeat) Glushkov(g *glushkovBuilder) glushkovSets {
+	return repeat.unfold().Glushkov(g)
+}
+
+func (plus regExpNodePlus) Nullable() bool     { return plus.Next.Nullable() }
+func (regExpNodeOptional) Nullable() bool      { return true }
+func (repeat regExpNodeRepeat) Nullable() bool { return repeat.min == 0 || repeat.Next.Nullable() }
+
+func (plus regExpNodePlus) Derivative(by rune) regExpNode {
+	return aciNormalize(regExpNodeMul{[]regExpNode{plus.Next.Derivative(by), regExpNodeClini{plus.Next}}})
+}
+
+func (optional regExpNodeOptional) Derivative(by rune) regExpNode {
+	return optional.Next.Derivative(by)
+}
+
+func (repeat regExpNodeRepeat) Derivative(by rune) regExpNode {
+	if repeat.max == 0 {
+		return regExpNodeEmptySet{}
+	}
+	if repeat.min == 0 && repeat.max == -1 {
+		return regExpNodeClini{repeat.Next}.Derivative(by)
+	}
+
+	rest := repeat.rest()
+	res := regExpNode(regExpNodeMul{[]regExpNode{repeat.Next.Derivative(by), rest}})
+	if repeat.min > 0 && repeat.Next.Nullable() {
+		res = regExpNodeAdd{[]regExpNode{res, rest.Derivative(by)}}
+	}
+
+	return aciNormalize(res)
+}
+
+func (plus regExpNodePlus) PartialDerivative(by rune) regExpNodeSet {
+	return plus.Next.PartialDerivative(by).concat(regExpNodeClini{plus.Next})
+}
+
+func (optional regExpNodeOptional) PartialDerivative(by rune) regExpNodeSet {
+	return optional.Next.PartialDerivative(by)
+}
+
+func (repeat regExpNodeRepeat) PartialDerivative(by rune) regExpNodeSet {
+	if repeat.max == 0 {
+		return regExpNodeSet{}
+	}
+	if repeat.min == 0 && repeat.max == -1 {
+		return regExpNodeClini{repeat.Next}.PartialDerivative(by)
+	}
+
+	rest := repeat.rest()
+	res := repeat.Next.PartialDerivative(by).concat(rest)
+	if repeat.min > 0 && repeat.Next.Nullable() {
+		maps.Copy(res, rest.PartialDerivative(by))
+	}
+
+	return res
+}
@@ -0,0 +1,109 @@
+package formallang
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// extendedInputs - extended expressions without 0 and 1, their syntax is accepted by Go regexp as is
+var extendedInputs = []string{
+	"a?b",
+	"(ab)+",
+	"a+b?a+",
+	"a{3}",
+	"(a|b){2,}",
+	"a{0,}b",
+	"a{1,3}b",
+	"(ab?){2}",
+	"(a{2}|b)*",
+	"((a|b)?){2,3}",
+}
+
+func TestExtendedAgreesWithGoRegexp(t *testing.T) {
+	for _, input := range extendedInputs {
+		reg := mustRegExpWithSyntax(t, input, SyntaxExtended)
+		want := regexp.MustCompile("^(?:" + input + ")$")
+		dfa := DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())
+
+		// c is out of alphabet of every input
+		for _, word := range words([]rune("abc"), 7) {
+			if got, expected := dfa.Accepts(word), want.MatchString(word); got != expected {
+				t.Errorf("%v: %q: dfa accepts %v, want %v", input, word, got, expected)
+			}
+		}
+	}
+}
+
+func TestExtendedToStringRoundTrip(t *testing.T) {
+	for _, input := range extendedInputs {
+		reg := mustRegExpWithSyntax(t, input, SyntaxExtended)
+
+		str := reg.ToString()
+		parsed := mustRegExpWithSyntax(t, str, SyntaxExtended)
+		if !reflect.DeepEqual(reg, parsed) {
+			t.Errorf("%v: %v parses to other expression %v", input, str, parsed.ToString())
+		}
+	}
+}
+
+func TestExtendedToClassic(t *testing.T) {
+	tests := []struct {
+		str, want string
+	}{
+		{"a?b", "(a + 1)b"},
+		{"(ab)+", "ab(ab)*"},
+		{"a{3}", "aaa"},
+		{"a{0}b", "1b"},
+		{"(a|b){2,}", "(a + b)(a + b)(a + b)*"},
+		{"a{1,3}b", "a(a + 1)(a + 1)b"},
+		{"ab?c+", "a(b + 1)cc*"},
+	}
+
+	for _, test := range tests {
+		reg := mustRegExpWithSyntax(t, test.str, SyntaxExtended)
+
+		got := reg.tree.ToString(lowPriority, SyntaxClassic)
+		if got != test.want {
+			t.Errorf("%v: classic form is %v, want %v", test.str, got, test.want)
+			continue
+		}
+
+		classic := mustRegExp(t, got)
+		classic.abc = reg.abc
+		if want, got := minimalDFADOT(t, DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())),
+			minimalDFADOT(t, DFAfromNFA(NFAFromRegExp(classic).RemoveEmpty())); want != got {
+			t.Errorf("%v: classic form %v has other language\n%v\n%v", test.str, test.want, want, got)
+		}
+	}
+}
+
+func TestExtendedParseErrors(t *testing.T) {
+	tests := []struct {
+		str, want string
+	}{
+		{"a{3,1}", "can't parse on column 2"},
+		{"a{", "can't parse on column 3"},
+		{"{", "can't parse on column 1"},
+		{"a{,2}", "can't parse on column 3"},
+		{"a{2", "can't parse on column 4"},
+		{"a{2,", "can't parse on column 5"},
+	}
+
+	for _, test := range tests {
+		tokens, err := TokenizeWithSyntax(test.str, SyntaxExtended)
+		if err != nil {
+			t.Fatalf("%q: %v", test.str, err)
+		}
+
+		_, err = RegExpFromTokensWithSyntax(tokens, SyntaxExtended)
+		if err == nil {
+			t.Errorf("%q: expected error %q", test.str, test.want)
+			continue
+		}
+
+		if err.Error() != test.want {
+			t.Errorf("%q: got error %q, want %q", test.str, err, test.want)
+		}
+	}
+}
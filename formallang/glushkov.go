@@ -0,0 +1,128 @@
+package formallang
+
+import "maps"
+
+// glushkovSets - first and last positions of subexpression, nullable if it accepts empty word
+type glushkovSets struct {
+	nullable    bool
+	first, last []int
+}
+
+// glushkovBuilder - positions of linearized regular expression and follow relation between them,
+// position of character class is labeled by all its symbols
+type glushkovBuilder struct {
+	abc       []rune
+	positions [][]rune
+	follow    []map[int]struct{}
+}
+
+func (g *glushkovBuilder) addPosition(runes ...rune) int {
+	g.positions = append(g.positions, runes)
+	g.follow = append(g.follow, make(map[int]struct{}))
+	return len(g.positions) - 1
+}
+
+func (g *glushkovBuilder) link(last, first []int) {
+	for _, from := range last {
+		for _, to := range first {
+			g.follow[from][to] = struct{}{}
+		}
+	}
+}
+
+func (regExpNodeEmptyRune) Glushkov(*glushkovBuilder) glushkovSets {
+	return glushkovSets{nullable: true}
+}
+
+func (regExpNodeEmptySet) Glushkov(*glushkovBuilder) glushkovSets {
+	return glushkovSets{nullable: false}
+}
+
+func (r regExpNodeRune) Glushkov(g *glushkovBuilder) glushkovSets {
+	pos := g.addPosition(r.r)
+	return glushkovSets{nullable: false, first: []int{pos}, last: []int{pos}}
+}
+
+func (add regExpNodeAdd) Glushkov(g *glushkovBuilder) glushkovSets {
+	res := glushkovSets{}
+	for _, next := range add.Next {
+		sets := next.Glushkov(g)
+
+		res.nullable = res.nullable || sets.nullable
+		res.first = append(res.first, sets.first...)
+		res.last = append(res.last, sets.last...)
+	}
+
+	return res
+}
+
+func (mul regExpNodeMul) Glushkov(g *glushkovBuilder) glushkovSets {
+	res := glushkovSets{nullable: true}
+	for _, next := range mul.Next {
+		sets := next.Glushkov(g)
+
+		g.link(res.last, sets.first)
+
+		if res.nullable {
+			res.first = append(res.first, sets.first...)
+		}
+		if sets.nullable {
+			res.last = append(res.last, sets.last...)
+		} else {
+			res.last = append([]int(nil), sets.last...)
+		}
+		res.nullable = res.nullable && sets.nullable
+	}
+
+	return res
+}
+
+func (clini regExpNodeClini) Glushkov(g *glushkovBuilder) glushkovSets {
+	sets := clini.Next.Glushkov(g)
+	g.link(sets.last, sets.first)
+
+	sets.nullable = true
+	return sets
+}
+
+// GlushkovNFA - constructs NFA without empty transitions, which states are positions of regular expression
+func GlushkovNFA(reg *RegExp) *NFA {
+	nfa := &NFA{
+		abc:   maps.Clone(reg.abc),
+		nodes: make(map[*nfanode]struct{}),
+	}
+
+	g := &glushkovBuilder{abc: unionAlphabet(reg.abc)}
+	sets := reg.tree.Glushkov(g)
+
+	nfa.start = nfa.newNode()
+	nfa.start.linkscnt++
+	nfa.start.endpoint = sets.nullable
+
+	nodes := make([]*nfanode, len(g.positions))
+	for pos := range nodes {
+		nodes[pos] = nfa.newNode()
+	}
+
+	for _, pos := range sets.last {
+		nodes[pos].endpoint = true
+	}
+
+	for _, to := range sets.first {
+		for _, r := range g.positions[to] {
+			nfa.start.link(r, nodes[to])
+		}
+	}
+
+	for from, follow := range g.follow {
+		for to := range follow {
+			for _, r := range g.positions[to] {
+				nodes[from].link(r, nodes[to])
+			}
+		}
+	}
+
+	nfa.removeNoLinks()
+
+	return nfa
+}
@@ -0,0 +1,25 @@
+package formallang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGlushkovStates(t *testing.T) {
+	for _, input := range regExpInputs(t, 300) {
+		nfa := GlushkovNFA(mustRegExp(t, input))
+
+		// every letter is a position, start state is added to them
+		positions := strings.Count(input, "a") + strings.Count(input, "b")
+		if len(nfa.nodes) != positions+1 {
+			t.Errorf("%v: Glushkov NFA has %v states, want %v", input, len(nfa.nodes), positions+1)
+		}
+
+		for node := range nfa.nodes {
+			if len(node.empty) != 0 {
+				t.Errorf("%v: Glushkov NFA has empty transitions", input)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package formallang
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update - regenerates DOT goldens and their png images in test/testN
+var update = flag.Bool("update", false, "regenerate golden files of test/testN")
+
+// goldenStages - pipeline stages, that have golden file named after stage in every test/testN
+var goldenStages = []struct {
+	name  string
+	build func(reg *RegExp) dotWriter
+}{
+	{"0_nfa", func(reg *RegExp) dotWriter {
+		return NFAFromRegExp(reg)
+	}},
+	{"1_nfa_without_empty", func(reg *RegExp) dotWriter {
+		return NFAFromRegExp(reg).RemoveEmpty()
+	}},
+	{"2_dfa", func(reg *RegExp) dotWriter {
+		return DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())
+	}},
+	{"3_cdfa", func(reg *RegExp) dotWriter {
+		return CDFAfromDFA(DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty()))
+	}},
+	{"4_mcdfa", func(reg *RegExp) dotWriter {
+		return CDFAfromDFA(DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())).Minimise()
+	}},
+}
+
+// firstDiff - returns number of first different line counted from 1
+func firstDiff(a, b []byte) int {
+	linesA, linesB := strings.Split(string(a), "\n"), strings.Split(string(b), "\n")
+
+	for i := 0; i < len(linesA) && i < len(linesB); i++ {
+		if linesA[i] != linesB[i] {
+			return i + 1
+		}
+	}
+
+	return min(len(linesA), len(linesB)) + 1
+}
+
+func TestGoldenStages(t *testing.T) {
+	for dir, input := range stageInputs(t) {
+		reg := mustRegExp(t, input)
+
+		for _, stage := range goldenStages {
+			automaton := stage.build(reg)
+			got := &bytes.Buffer{}
+			if err := automaton.WriteDOT(got); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join(dir, stage.name+".dot")
+			if *update {
+				if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if err := dump(automaton, filepath.Join(dir, stage.name+".png"), FormatPNG); err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("%v: %v differs from golden on line %v, run go test ./formallang -run TestGoldenStages -update", golden, input, firstDiff(got.Bytes(), want))
+			}
+		}
+	}
+}
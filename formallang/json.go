@@ -0,0 +1,357 @@
+package formallang
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// transitionJSON - transition by symbol, or empty transition without symbol
+type transitionJSON struct {
+	From   int    `json:"from"`
+	Symbol string `json:"symbol,omitempty"`
+	Empty  bool   `json:"empty,omitempty"`
+	To     int    `json:"to"`
+}
+
+type automatonJSON struct {
+	Alphabet []string `json:"alphabet"`
+	States   []int    `json:"states"`
+	// Start - is absent only for DFA without states, that accepts nothing
+	Start       *int             `json:"start,omitempty"`
+	Accepting   []int            `json:"accepting"`
+	Sink        *int             `json:"sink,omitempty"`
+	Transitions []transitionJSON `json:"transitions"`
+}
+
+func alphabetJSON(abc map[rune]struct{}) []string {
+	alph := unionAlphabet(abc)
+	res := make([]string, len(alph))
+	for i, r := range alph {
+		res[i] = string(r)
+	}
+
+	return res
+}
+
+func dfaJSON(abc map[rune]struct{}, start, stock *dfanode) automatonJSON {
+	nodes := dfaNodesBFS(start)
+	ids := make(map[*dfanode]int, len(nodes))
+	for id, node := range nodes {
+		ids[node] = id
+	}
+	if _, ok := ids[stock]; !ok && stock != nil {
+		ids[stock] = len(nodes)
+		nodes = append(nodes, stock)
+	}
+
+	data := automatonJSON{
+		Alphabet:    alphabetJSON(abc),
+		States:      make([]int, len(nodes)),
+		Accepting:   make([]int, 0),
+		Transitions: make([]transitionJSON, 0),
+	}
+	if start != nil {
+		data.Start = new(int)
+	}
+
+	for id, node := range nodes {
+		data.States[id] = id
+		if node.endpoint {
+			data.Accepting = append(data.Accepting, id)
+		}
+
+		for _, r := range node.sortedRunes() {
+			data.Transitions = append(data.Transitions, transitionJSON{From: id, Symbol: string(r), To: ids[node.next[r]]})
+		}
+	}
+
+	if stock != nil {
+		sink := ids[stock]
+		data.Sink = &sink
+	}
+
+	return data
+}
+
+// check - validates ids and symbols, returns alphabet
+func (data automatonJSON) check(allowEmpty bool) (map[rune]struct{}, error) {
+	abc := make(map[rune]struct{})
+	for _, str := range data.Alphabet {
+		r, size := utf8.DecodeRuneInString(str)
+		if size == 0 || size != len(str) {
+			return nil, fmt.Errorf("alphabet symbol %q is not a single rune", str)
+		}
+
+		abc[r] = struct{}{}
+	}
+
+	states := make(map[int]struct{})
+	for _, id := range data.States {
+		if _, ok := states[id]; ok {
+			return nil, fmt.Errorf("duplicate state %v", id)
+		}
+
+		states[id] = struct{}{}
+	}
+
+	checkState := func(id int) error {
+		if _, ok := states[id]; !ok {
+			return fmt.Errorf("unknown state %v", id)
+		}
+
+		return nil
+	}
+
+	if data.Start != nil {
+		if err := checkState(*data.Start); err != nil {
+			return nil, err
+		}
+	}
+	if data.Sink != nil {
+		if err := checkState(*data.Sink); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range data.Accepting {
+		if err := checkState(id); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, transition := range data.Transitions {
+		if err := checkState(transition.From); err != nil {
+			return nil, err
+		}
+		if err := checkState(transition.To); err != nil {
+			return nil, err
+		}
+
+		if transition.Empty {
+			if !allowEmpty {
+				return nil, fmt.Errorf("empty transitions are not allowed")
+			}
+			if transition.Symbol != "" {
+				return nil, fmt.Errorf("empty transition %v -> %v has symbol %q", transition.From, transition.To, transition.Symbol)
+			}
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(transition.Symbol)
+		if size == 0 || size != len(transition.Symbol) {
+			return nil, fmt.Errorf("transition symbol %q is not a single rune", transition.Symbol)
+		}
+
+		if _, ok := abc[r]; !ok {
+			return nil, fmt.Errorf("transition symbol %q is not in alphabet", transition.Symbol)
+		}
+	}
+
+	return abc, nil
+}
+
+// dfanodes - creates dfanodes described by data
+func (data automatonJSON) dfanodes(newNode func() *dfanode) (map[int]*dfanode, error) {
+	nodes := make(map[int]*dfanode, len(data.States))
+	for _, id := range data.States {
+		nodes[id] = newNode()
+	}
+
+	for _, id := range data.Accepting {
+		nodes[id].endpoint = true
+	}
+
+	for _, transition := range data.Transitions {
+		r, _ := utf8.DecodeRuneInString(transition.Symbol)
+
+		from := nodes[transition.From]
+		if _, ok := from.next[r]; ok {
+			return nil, fmt.Errorf("state %v has several transitions by %q", transition.From, transition.Symbol)
+		}
+
+		from.link(r, nodes[transition.To])
+	}
+
+	return nodes, nil
+}
+
+// MarshalJSON - encodes NFA into JSON
+func (nfa NFA) MarshalJSON() ([]byte, error) {
+	nodes, ids := nfa.numerate()
+
+	data := automatonJSON{
+		Alphabet:    alphabetJSON(nfa.abc),
+		States:      make([]int, len(nodes)),
+		Start:       new(int),
+		Accepting:   make([]int, 0),
+		Transitions: make([]transitionJSON, 0),
+	}
+
+	for id, node := range nodes {
+		data.States[id] = id
+		if node.endpoint {
+			data.Accepting = append(data.Accepting, id)
+		}
+
+		from := len(data.Transitions)
+		for to := range node.empty {
+			data.Transitions = append(data.Transitions, transitionJSON{From: id, Empty: true, To: ids[to]})
+		}
+		for r, links := range node.next {
+			for to := range links {
+				data.Transitions = append(data.Transitions, transitionJSON{From: id, Symbol: string(r), To: ids[to]})
+			}
+		}
+
+		added := data.Transitions[from:]
+		sort.Slice(added, func(i, j int) bool {
+			if added[i].Empty != added[j].Empty {
+				return added[i].Empty
+			}
+			if added[i].Symbol != added[j].Symbol {
+				return added[i].Symbol < added[j].Symbol
+			}
+			return added[i].To < added[j].To
+		})
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON - decodes NFA from JSON
+func (nfa *NFA) UnmarshalJSON(bytes []byte) error {
+	var data automatonJSON
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return err
+	}
+
+	abc, err := data.check(true)
+	if err != nil {
+		return err
+	}
+	if data.Start == nil {
+		return fmt.Errorf("start state is missing")
+	}
+
+	*nfa = NFA{
+		abc:   abc,
+		nodes: make(map[*nfanode]struct{}),
+	}
+
+	nodes := make(map[int]*nfanode, len(data.States))
+	for _, id := range data.States {
+		nodes[id] = nfa.newNode()
+	}
+
+	nfa.start = nodes[*data.Start]
+	nfa.start.linkscnt++
+
+	for _, id := range data.Accepting {
+		nodes[id].endpoint = true
+	}
+
+	for _, transition := range data.Transitions {
+		if transition.Empty {
+			nodes[transition.From].linkEmpty(nodes[transition.To])
+			continue
+		}
+
+		r, _ := utf8.DecodeRuneInString(transition.Symbol)
+		nodes[transition.From].link(r, nodes[transition.To])
+	}
+
+	return nil
+}
+
+// MarshalJSON - encodes DFA into JSON
+func (dfa DFA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dfaJSON(dfa.abc, dfa.start, nil))
+}
+
+// UnmarshalJSON - decodes DFA from JSON
+func (dfa *DFA) UnmarshalJSON(bytes []byte) error {
+	var data automatonJSON
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return err
+	}
+
+	abc, err := data.check(false)
+	if err != nil {
+		return err
+	}
+
+	res := DFA{
+		abc:   abc,
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	nodes, err := data.dfanodes(res.newNode)
+	if err != nil {
+		return err
+	}
+
+	if data.Start != nil {
+		res.start = nodes[*data.Start]
+	}
+	*dfa = res
+	return nil
+}
+
+// MarshalJSON - encodes CDFA into JSON
+func (cdfa CDFA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dfaJSON(cdfa.abc, cdfa.start, cdfa.stock))
+}
+
+// UnmarshalJSON - decodes CDFA from JSON
+func (cdfa *CDFA) UnmarshalJSON(bytes []byte) error {
+	var data automatonJSON
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return err
+	}
+
+	abc, err := data.check(false)
+	if err != nil {
+		return err
+	}
+
+	if data.Start == nil {
+		return fmt.Errorf("start state is missing")
+	}
+	if data.Sink == nil {
+		return fmt.Errorf("sink state is missing")
+	}
+
+	res := CDFA{
+		abc:   abc,
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	nodes, err := data.dfanodes(res.newNode)
+	if err != nil {
+		return err
+	}
+
+	for id, node := range nodes {
+		for r := range abc {
+			if _, ok := node.next[r]; !ok {
+				return fmt.Errorf("state %v has no transition by %q", id, string(r))
+			}
+		}
+	}
+
+	stock := nodes[*data.Sink]
+	if stock.endpoint {
+		return fmt.Errorf("sink state %v is accepting", *data.Sink)
+	}
+	for r := range abc {
+		if stock.next[r] != stock {
+			return fmt.Errorf("sink state %v doesn't loop by %q", *data.Sink, string(r))
+		}
+	}
+
+	res.start = nodes[*data.Start]
+	res.stock = stock
+	*cdfa = res
+	return nil
+}
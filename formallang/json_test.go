@@ -0,0 +1,157 @@
+package formallang
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// emptyLanguage - minimal CDFA without accepting states, its start is sink
+func emptyLanguage(t *testing.T) *CDFA {
+	t.Helper()
+
+	return CDFAfromDFA(DFAfromNFA(NFAFromRegExp(mustRegExp(t, "a")).RemoveEmpty())).Intersect(
+		CDFAfromDFA(DFAfromNFA(NFAFromRegExp(mustRegExp(t, "b")).RemoveEmpty()))).Minimise()
+}
+
+// checkJSONRoundTrip - decodes encoded automaton into dst and checks, that it is encoded the same way
+func checkJSONRoundTrip(t *testing.T, name string, src, dst json.Marshaler) {
+	t.Helper()
+
+	data, err := src.MarshalJSON()
+	if err != nil {
+		t.Fatalf("%v: %v", name, err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		t.Fatalf("%v: %v\n%s", name, err, data)
+	}
+
+	again, err := dst.MarshalJSON()
+	if err != nil {
+		t.Fatalf("%v: %v", name, err)
+	}
+	if string(data) != string(again) {
+		t.Errorf("%v: round trip changed JSON\n%s\n%s", name, data, again)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for dir, input := range stageInputs(t) {
+		reg := mustRegExp(t, input)
+		nfa := NFAFromRegExp(reg)
+		dfa := DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())
+		cdfa := CDFAfromDFA(dfa)
+
+		nfaCopy := &NFA{}
+		checkJSONRoundTrip(t, dir+" nfa", nfa, nfaCopy)
+		if want, got := dotString(t, nfa), dotString(t, nfaCopy); want != got {
+			t.Errorf("%v: decoded nfa differs\n%v\n%v", dir, want, got)
+		}
+
+		dfaCopy := &DFA{}
+		checkJSONRoundTrip(t, dir+" dfa", dfa, dfaCopy)
+		if want, got := dotString(t, dfa), dotString(t, dfaCopy); want != got {
+			t.Errorf("%v: decoded dfa differs\n%v\n%v", dir, want, got)
+		}
+
+		cdfaCopy := &CDFA{}
+		checkJSONRoundTrip(t, dir+" cdfa", cdfa, cdfaCopy)
+		if want, got := dotString(t, cdfa), dotString(t, cdfaCopy); want != got {
+			t.Errorf("%v: decoded cdfa differs\n%v\n%v", dir, want, got)
+		}
+	}
+}
+
+func TestJSONRoundTripEmptyLanguage(t *testing.T) {
+	cdfa := emptyLanguage(t)
+	dfa := DFAfromCDFA(cdfa)
+	if dfa.start != nil {
+		t.Fatalf("dfa of empty language has start state")
+	}
+
+	dfaCopy := &DFA{}
+	checkJSONRoundTrip(t, "dfa", dfa, dfaCopy)
+	if dfaCopy.start != nil {
+		t.Errorf("decoded dfa has start state")
+	}
+	if dfaCopy.Accepts("") {
+		t.Errorf("decoded dfa accepts empty word")
+	}
+
+	cdfaCopy := &CDFA{}
+	checkJSONRoundTrip(t, "cdfa", cdfa, cdfaCopy)
+	if cdfaCopy.start != cdfaCopy.stock {
+		t.Errorf("decoded cdfa start isn't sink")
+	}
+}
+
+func TestJSONRequiresStart(t *testing.T) {
+	data := []byte(`{"alphabet":["a"],"states":[0],"accepting":[],"sink":0,"transitions":[{"from":0,"symbol":"a","to":0}]}`)
+
+	if err := json.Unmarshal(data, &NFA{}); err == nil {
+		t.Errorf("nfa without start state is decoded")
+	}
+	if err := json.Unmarshal(data, &CDFA{}); err == nil {
+		t.Errorf("cdfa without start state is decoded")
+	}
+	if err := json.Unmarshal(data, &DFA{}); err != nil {
+		t.Errorf("dfa without start state: %v", err)
+	}
+}
+
+func TestJSONLiteralEpsilon(t *testing.T) {
+	// ε is letter here, Thompson NFA also has empty transitions
+	nfa := NFAFromRegExp(mustRegExp(t, "(ε + 1)a"))
+
+	nfaCopy := &NFA{}
+	checkJSONRoundTrip(t, "nfa", nfa, nfaCopy)
+	if want, got := dotString(t, nfa), dotString(t, nfaCopy); want != got {
+		t.Errorf("decoded nfa differs\n%v\n%v", want, got)
+	}
+
+	for word, want := range map[string]bool{"a": true, "εa": true, "εεa": false, "ε": false} {
+		if got := nfaCopy.Accepts(word); got != want {
+			t.Errorf("decoded nfa accepts %q: %v, want %v", word, got, want)
+		}
+	}
+}
+
+func TestJSONEmptyTransition(t *testing.T) {
+	tests := []struct {
+		data string
+		ok   bool
+		// word - accepted by decoded automaton
+		word string
+	}{
+		{`{"alphabet":[],"states":[0,1],"start":0,"accepting":[1],"transitions":[{"from":0,"empty":true,"to":1}]}`, true, ""},
+		{`{"alphabet":["ε"],"states":[0,1],"start":0,"accepting":[1],"transitions":[{"from":0,"symbol":"ε","to":1}]}`, true, "ε"},
+		{`{"alphabet":[],"states":[0,1],"start":0,"accepting":[1],"transitions":[{"from":0,"symbol":"ε","to":1}]}`, false, ""},
+		{`{"alphabet":["a"],"states":[0,1],"start":0,"accepting":[1],"transitions":[{"from":0,"symbol":"a","empty":true,"to":1}]}`, false, ""},
+	}
+
+	for _, test := range tests {
+		nfa := &NFA{}
+		err := json.Unmarshal([]byte(test.data), nfa)
+		if (err == nil) != test.ok {
+			t.Errorf("%v: got error %v", test.data, err)
+			continue
+		}
+		if err == nil && !nfa.Accepts(test.word) {
+			t.Errorf("%v: decoded nfa doesn't accept %q", test.data, test.word)
+		}
+	}
+}
+
+func TestJSONRejectsBadSink(t *testing.T) {
+	tests := []string{
+		// accepting
+		`{"alphabet":["a"],"states":[0],"start":0,"accepting":[0],"sink":0,"transitions":[{"from":0,"symbol":"a","to":0}]}`,
+		// leaves by a
+		`{"alphabet":["a"],"states":[0,1],"start":0,"accepting":[1],"sink":0,"transitions":[{"from":0,"symbol":"a","to":1},{"from":1,"symbol":"a","to":0}]}`,
+	}
+
+	for _, data := range tests {
+		if err := json.Unmarshal([]byte(data), &CDFA{}); err == nil {
+			t.Errorf("%v: bad sink is decoded", data)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package formallang
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomCDFA - complete automaton with n random states over first symbols of alphabet and separate stock
+func randomCDFA(rng *rand.Rand, n, symbols int) *CDFA {
+	cdfa := &CDFA{
+		abc:   make(map[rune]struct{}),
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	alph := []rune("abcdefghijklmnopqrstuvwxyz")[:symbols]
+	for _, r := range alph {
+		cdfa.abc[r] = struct{}{}
+	}
+
+	cdfa.stock = cdfa.newNode()
+	for _, r := range alph {
+		cdfa.stock.link(r, cdfa.stock)
+	}
+
+	nodes := make([]*dfanode, n)
+	for i := range nodes {
+		nodes[i] = cdfa.newNode()
+		nodes[i].endpoint = rng.Intn(3) == 0
+	}
+	nodes = append(nodes, cdfa.stock)
+
+	for _, from := range nodes[:n] {
+		for _, r := range alph {
+			from.link(r, nodes[rng.Intn(len(nodes))])
+		}
+	}
+
+	cdfa.start = nodes[0]
+	return cdfa
+}
+
+// mooreMinimise - reference minimisation by refinement of string signatures, as it was before Hopcroft's algorithm
+func mooreMinimise(cdfa *CDFA) *CDFA {
+	nodeClasses := make(map[*dfanode]string)
+	initial := make(map[string]struct{})
+	for node := range cdfa.nodes {
+		nodeClasses[node] = "0"
+		if node.endpoint {
+			nodeClasses[node] = "1"
+		}
+		initial[nodeClasses[node]] = struct{}{}
+	}
+	classesCnt := len(initial)
+
+	alph := unionAlphabet(cdfa.abc)
+	for {
+		bufNodeClasses := make(map[*dfanode]string)
+		bufClasses := make(map[string]int)
+
+		for from, fromclass := range nodeClasses {
+			builder := &strings.Builder{}
+			builder.WriteString(fromclass)
+			for _, r := range alph {
+				builder.WriteRune(',')
+				builder.WriteString(nodeClasses[from.next[r]])
+			}
+
+			signature := builder.String()
+			if _, ok := bufClasses[signature]; !ok {
+				bufClasses[signature] = len(bufClasses)
+			}
+
+			bufNodeClasses[from] = fmt.Sprint(bufClasses[signature])
+		}
+
+		nodeClasses = bufNodeClasses
+		if len(bufClasses) == classesCnt {
+			break
+		}
+		classesCnt = len(bufClasses)
+	}
+
+	mcdfa := &CDFA{
+		abc:   maps.Clone(cdfa.abc),
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	classesToNodes := make(map[string]*dfanode)
+	for _, class := range nodeClasses {
+		if _, ok := classesToNodes[class]; !ok {
+			classesToNodes[class] = mcdfa.newNode()
+		}
+	}
+
+	mcdfa.start = classesToNodes[nodeClasses[cdfa.start]]
+	mcdfa.stock = classesToNodes[nodeClasses[cdfa.stock]]
+
+	for from, fromclass := range nodeClasses {
+		mfrom := classesToNodes[fromclass]
+		mfrom.endpoint = from.endpoint
+
+		for r, to := range from.next {
+			mfrom.link(r, classesToNodes[nodeClasses[to]])
+		}
+	}
+
+	return mcdfa
+}
+
+func TestMinimiseMatchesMoore(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		cdfa := randomCDFA(rng, 1+rng.Intn(30), 1+rng.Intn(3))
+
+		hopcroft, moore := &bytes.Buffer{}, &bytes.Buffer{}
+		if err := cdfa.Minimise().WriteDOT(hopcroft); err != nil {
+			t.Fatal(err)
+		}
+		if err := mooreMinimise(cdfa).WriteDOT(moore); err != nil {
+			t.Fatal(err)
+		}
+
+		if hopcroft.String() != moore.String() {
+			t.Fatalf("minimal automata differ\n%v\n%v", hopcroft, moore)
+		}
+
+		if ok, word := Equivalent(DFAfromCDFA(cdfa), DFAfromCDFA(cdfa.Minimise())); !ok {
+			t.Fatalf("minimisation changes language, distinguishing word %q", word)
+		}
+	}
+}
+
+func benchmarkMinimise(b *testing.B, minimise func(*CDFA) *CDFA) {
+	for _, n := range []int{100, 1000, 10000} {
+		cdfa := randomCDFA(rand.New(rand.NewSource(int64(n))), n, 4)
+
+		b.Run(fmt.Sprintf("states=%v", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				minimise(cdfa)
+			}
+		})
+	}
+}
+
+func BenchmarkMinimiseHopcroft(b *testing.B) {
+	benchmarkMinimise(b, func(cdfa *CDFA) *CDFA { return cdfa.Minimise() })
+}
+
+func BenchmarkMinimiseMoore(b *testing.B) {
+	benchmarkMinimise(b, mooreMinimise)
+}
@@ -1,30 +1,35 @@
 package formallang
 
 import (
-	"fmt"
-	"log"
 	"maps"
-
-	"github.com/goccy/go-graphviz"
-	"github.com/goccy/go-graphviz/cgraph"
+	"sort"
 )
 
-const (
-	// EmptyRune - is empty transition move key
-	EmptyRune = rune('$')
-)
+// emptySymbol - denotes empty transition in DOT and text, the same letter of alphabet is escaped by backslash
+const emptySymbol = "ε"
+
+// EmptyRune - was the key of empty transitions, text format still reads "$" symbol as empty transition
+//
+// Deprecated: empty transitions are kept apart from letters, so '$' is an ordinary letter of alphabet,
+// use NFABuilder.EmptyEdge to add empty transition.
+const EmptyRune = rune('$')
 
 // NFA - imlement nondeterministic finite automaton with one letter transition
 type NFA struct {
-	abc   map[rune]struct{}
-	nodes map[*nfanode]struct{}
-	start *nfanode
+	abc     map[rune]struct{}
+	nodes   map[*nfanode]struct{}
+	start   *nfanode
+	created int
 }
 
 type nfanode struct {
-	next     map[rune]map[*nfanode]struct{}
+	next map[rune]map[*nfanode]struct{}
+	// empty - ends of empty transitions, kept apart so any rune can be in alphabet
+	empty    map[*nfanode]struct{}
 	linkscnt int
 	endpoint bool
+	// order - number of node in order of creation
+	order int
 }
 
 func (from *nfanode) link(r rune, to *nfanode) *nfanode {
@@ -32,6 +37,10 @@ func (from *nfanode) link(r rune, to *nfanode) *nfanode {
 		from.next[r] = map[*nfanode]struct{}{}
 	}
 
+	if _, ok := from.next[r][to]; ok {
+		return from
+	}
+
 	if from != to {
 		to.linkscnt++
 	}
@@ -41,7 +50,7 @@ func (from *nfanode) link(r rune, to *nfanode) *nfanode {
 }
 
 func (from *nfanode) unlink(r rune, to *nfanode) *nfanode {
-	if from.next[r] == nil {
+	if _, ok := from.next[r][to]; !ok {
 		return from
 	}
 
@@ -53,6 +62,32 @@ func (from *nfanode) unlink(r rune, to *nfanode) *nfanode {
 	return from
 }
 
+func (from *nfanode) linkEmpty(to *nfanode) *nfanode {
+	if _, ok := from.empty[to]; ok {
+		return from
+	}
+
+	if from != to {
+		to.linkscnt++
+	}
+
+	from.empty[to] = struct{}{}
+	return from
+}
+
+func (from *nfanode) unlinkEmpty(to *nfanode) *nfanode {
+	if _, ok := from.empty[to]; !ok {
+		return from
+	}
+
+	if from != to {
+		to.linkscnt--
+	}
+
+	delete(from.empty, to)
+	return from
+}
+
 // NFAFromRegExp - constructs new NFA with given regular expression
 func NFAFromRegExp(reg *RegExp) *NFA {
 	res := &NFA{
@@ -70,143 +105,179 @@ func NFAFromRegExp(reg *RegExp) *NFA {
 	return res
 }
 
-// RemoveEmpty - removes emty links
+// RemoveEmpty - removes emty links in place, WithoutEpsilon keeps original NFA
 func (nfa *NFA) RemoveEmpty() *NFA {
+	closures := make(map[*nfanode]map[*nfanode]struct{})
 	for from := range nfa.nodes {
-		if _, ok := from.next[EmptyRune][from]; ok {
-			delete(from.next[EmptyRune], from)
-		}
+		closures[from] = nfa.emptyClosure(map[*nfanode]struct{}{from: {}})
 	}
 
-	for from := range nfa.nodes {
-		emptyReleased := false
-
-		for !emptyReleased {
-			emptyReleased = true
-			for to := range from.next[EmptyRune] {
-				if to.endpoint {
-					from.endpoint = true
-				}
+	for from, closure := range closures {
+		for node := range closure {
+			if node == from {
+				continue
+			}
 
-				from.unlink(EmptyRune, to)
-				for r, tonext := range to.next {
-					if r == EmptyRune {
-						emptyReleased = false
-					}
+			if node.endpoint {
+				from.endpoint = true
+			}
 
-					for node := range tonext {
-						from.link(r, node)
-					}
+			for r, tonext := range node.next {
+				for to := range tonext {
+					from.link(r, to)
 				}
 			}
 		}
 	}
 
+	for from := range nfa.nodes {
+		for to := range from.empty {
+			from.unlinkEmpty(to)
+		}
+	}
+
 	nfa.removeNoLinks()
 
 	return nfa
 }
 
-func (nfa *NFA) newNode() *nfanode {
-	res := nfanode{
-		next:     make(map[rune]map[*nfanode]struct{}),
-		linkscnt: 0,
-		endpoint: false,
-	}
+// Accepts - checks if word belongs to language of NFA
+func (nfa *NFA) Accepts(word string) bool {
+	curr := nfa.emptyClosure(map[*nfanode]struct{}{nfa.start: {}})
 
-	nfa.nodes[&res] = struct{}{}
-	return &res
-}
+	for _, r := range word {
+		next := make(map[*nfanode]struct{})
+		for from := range curr {
+			for to := range from.next[r] {
+				next[to] = struct{}{}
+			}
+		}
 
-func (nfa *NFA) deleteNode(node *nfanode) {
-	delete(nfa.nodes, node)
-}
+		if len(next) == 0 {
+			return false
+		}
 
-func (nfa *NFA) removeNoLinks() {
-	for node := range nfa.nodes {
-		if node.linkscnt == 0 {
-			nfa.deleteNode(node)
+		curr = nfa.emptyClosure(next)
+	}
+
+	for node := range curr {
+		if node.endpoint {
+			return true
 		}
 	}
+
+	return false
 }
 
-// Dump - dumps NFA into png
-func (nfa NFA) Dump(filename string) {
-	g := graphviz.New()
-	graph, err := g.Graph(graphviz.StrictDirected)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func() {
-		if err := graph.Close(); err != nil {
-			log.Fatal(err)
+func (nfa *NFA) emptyClosure(nodes map[*nfanode]struct{}) map[*nfanode]struct{} {
+	res := maps.Clone(nodes)
+
+	var tasks queue
+	for node := range nodes {
+		tasks.Push(node)
+	}
+
+	for tasks.Size() > 0 {
+		from := tasks.Top().(*nfanode)
+		tasks.Pop()
+
+		for to := range from.empty {
+			if _, ok := res[to]; ok {
+				continue
+			}
+
+			res[to] = struct{}{}
+			tasks.Push(to)
 		}
-		g.Close()
-	}()
+	}
 
-	fromNFAtoGRAF := make(map[*nfanode]*cgraph.Node)
-	fromGRAFtoNFA := make(map[*cgraph.Node]*nfanode)
-	for nodeptr := range nfa.nodes {
-		graphnode, err := graph.CreateNode(fmt.Sprintf("%p", nodeptr))
+	return res
+}
 
-		nodeShape := "circle"
-		if nodeptr.endpoint {
-			nodeShape = "doublecircle"
+// numerate - gives dense ids to nodes reachable from start in breadth first order,
+// empty transitions go before labeled ones
+func (nfa *NFA) numerate() ([]*nfanode, map[*nfanode]int) {
+	nodes := []*nfanode{nfa.start}
+	ids := map[*nfanode]int{nfa.start: 0}
+
+	visit := func(next map[*nfanode]struct{}) {
+		links := make([]*nfanode, 0, len(next))
+		for to := range next {
+			if _, ok := ids[to]; !ok {
+				links = append(links, to)
+			}
 		}
+		sort.Slice(links, func(i, j int) bool { return links[i].order < links[j].order })
 
-		nodeLabel := ""
-		if nodeptr == nfa.start {
-			nodeLabel = "in"
+		for _, to := range links {
+			ids[to] = len(nodes)
+			nodes = append(nodes, to)
 		}
+	}
 
-		graphnode.SetLabel(nodeLabel).SetShape(cgraph.Shape(nodeShape))
+	for i := 0; i < len(nodes); i++ {
+		from := nodes[i]
 
-		fromGRAFtoNFA[graphnode] = nodeptr
-		fromNFAtoGRAF[nodeptr] = graphnode
-		if err != nil {
-			log.Fatal(err)
+		visit(from.empty)
+
+		runes := make([]rune, 0, len(from.next))
+		for r := range from.next {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+		for _, r := range runes {
+			visit(from.next[r])
 		}
 	}
 
-	buf := make(map[struct{ from, to *nfanode }]([]rune))
+	return nodes, ids
+}
 
-	for from := range nfa.nodes {
-		for r, links := range from.next {
-			for to := range links {
-				buf[struct{ from, to *nfanode }{from, to}] = append(buf[struct{ from, to *nfanode }{from, to}], r)
-			}
-		}
+func (nfa *NFA) newNode() *nfanode {
+	res := nfanode{
+		next:     make(map[rune]map[*nfanode]struct{}),
+		empty:    make(map[*nfanode]struct{}),
+		linkscnt: 0,
+		endpoint: false,
+		order:    nfa.created,
 	}
+	nfa.created++
 
-	for pair, runes := range buf {
-		edge, err := graph.CreateEdge(fmt.Sprintf("%p_%p", fromNFAtoGRAF[pair.from], fromNFAtoGRAF[pair.to]), fromNFAtoGRAF[pair.from], fromNFAtoGRAF[pair.to])
+	nfa.nodes[&res] = struct{}{}
+	return &res
+}
 
-		lable := fmt.Sprintf("%c", runes[0])
-		for _, r := range runes[1:] {
-			lable = fmt.Sprintf("%s,%c", lable, r)
-		}
+func (nfa *NFA) deleteNode(node *nfanode) {
+	delete(nfa.nodes, node)
+}
+
+func (nfa *NFA) removeNoLinks() {
+	removed := true
+	for removed {
+		removed = false
 
-		edge.SetLabel(lable)
+		for node := range nfa.nodes {
+			if node.linkscnt > 0 {
+				continue
+			}
+
+			for r, links := range node.next {
+				for to := range links {
+					node.unlink(r, to)
+				}
+			}
+			for to := range node.empty {
+				node.unlinkEmpty(to)
+			}
 
-		if err != nil {
-			log.Fatal(err)
+			nfa.deleteNode(node)
+			removed = true
 		}
 	}
+}
 
-	// 1. write encoded PNG data to buffer
-	//var buf bytes.Buffer
-	//if err := g.Render(graph, graphviz.PNG, &buf); err != nil {
-	//	log.Fatal(err)
-	//}
-	//
-	//// 2. get as image.Image instance
-	//image, err := g.RenderImage(graph)
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-	//
-	//// 3. write to file directly
-	if err := g.RenderFilename(graph, graphviz.PNG, filename); err != nil {
-		log.Fatal(err)
-	}
+// Dump - dumps NFA into png
+func (nfa NFA) Dump(filename string) error {
+	return dump(&nfa, filename, FormatPNG)
 }
@@ -1,6 +1,10 @@
 package formallang
 
-import "maps"
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
 
 // NFANodeInput - struct with description of every node
 type NFANodeInput struct {
@@ -16,7 +20,8 @@ type NFANodeInput struct {
 // NFAfromInput - creates NFA from given array of nodes descriptions
 func NFAfromInput(abc map[rune]struct{}, input []NFANodeInput) *NFA {
 	nfa := &NFA{
-		abc: maps.Clone(abc),
+		abc:   maps.Clone(abc),
+		nodes: make(map[*nfanode]struct{}),
 	}
 
 	idToNodes := make(map[string]*nfanode)
@@ -30,6 +35,7 @@ func NFAfromInput(abc map[rune]struct{}, input []NFANodeInput) *NFA {
 
 		if descr.start {
 			nfa.start = from
+			nfa.start.linkscnt++
 		}
 
 		for _, nextnode := range descr.next {
@@ -45,3 +51,137 @@ func NFAfromInput(abc map[rune]struct{}, input []NFANodeInput) *NFA {
 
 	return nfa
 }
+
+// NFABuilder - step by step constructor of NFA
+type NFABuilder struct {
+	abc     map[rune]struct{}
+	states  []string
+	ids     map[string]int
+	start   []string
+	accept  map[string]struct{}
+	edges   []nfaBuilderEdge
+	current string
+	err     error
+}
+
+type nfaBuilderEdge struct {
+	from, to string
+	r        rune
+	empty    bool
+}
+
+// NewNFABuilder - creates builder of NFA over given alphabet
+func NewNFABuilder(abc map[rune]struct{}) *NFABuilder {
+	return &NFABuilder{
+		abc:    maps.Clone(abc),
+		ids:    make(map[string]int),
+		accept: make(map[string]struct{}),
+	}
+}
+
+func (b *NFABuilder) fail(err error) *NFABuilder {
+	if b.err == nil {
+		b.err = err
+	}
+
+	return b
+}
+
+// State - declares new state, following Start and Accept are applied to it
+func (b *NFABuilder) State(id string) *NFABuilder {
+	if _, ok := b.ids[id]; ok {
+		return b.fail(fmt.Errorf("duplicate state %q", id))
+	}
+
+	b.ids[id] = len(b.states)
+	b.states = append(b.states, id)
+	b.current = id
+	return b
+}
+
+// Start - makes last declared state start
+func (b *NFABuilder) Start() *NFABuilder {
+	if len(b.states) == 0 {
+		return b.fail(fmt.Errorf("start is set before any state"))
+	}
+
+	b.start = append(b.start, b.current)
+	return b
+}
+
+// Accept - makes last declared state accepting
+func (b *NFABuilder) Accept() *NFABuilder {
+	if len(b.states) == 0 {
+		return b.fail(fmt.Errorf("accept is set before any state"))
+	}
+
+	b.accept[b.current] = struct{}{}
+	return b
+}
+
+// Edge - adds transition by symbol of alphabet, states that are never declared by State become non-accepting states
+func (b *NFABuilder) Edge(from string, r rune, to string) *NFABuilder {
+	if _, ok := b.abc[r]; !ok {
+		return b.fail(fmt.Errorf("symbol %q of edge %q -> %q is not in alphabet", r, from, to))
+	}
+
+	b.edges = append(b.edges, nfaBuilderEdge{from, to, r, false})
+	return b
+}
+
+// EmptyEdge - adds empty transition, its states are declared as in Edge
+func (b *NFABuilder) EmptyEdge(from, to string) *NFABuilder {
+	b.edges = append(b.edges, nfaBuilderEdge{from, to, 0, true})
+	return b
+}
+
+// Build - validates description and constructs NFA
+func (b *NFABuilder) Build() (*NFA, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	switch len(b.start) {
+	case 0:
+		return nil, fmt.Errorf("start state is missing")
+	case 1:
+	default:
+		return nil, fmt.Errorf("several start states: %q and %q", b.start[0], b.start[1])
+	}
+
+	nfa := &NFA{
+		abc:   maps.Clone(b.abc),
+		nodes: make(map[*nfanode]struct{}),
+	}
+
+	// states of edges are declared implicitly, so they may be used before State
+	states, ids := slices.Clone(b.states), maps.Clone(b.ids)
+	for _, edge := range b.edges {
+		for _, id := range []string{edge.from, edge.to} {
+			if _, ok := ids[id]; !ok {
+				ids[id] = len(states)
+				states = append(states, id)
+			}
+		}
+	}
+
+	nodes := make([]*nfanode, len(states))
+	for i, id := range states {
+		nodes[i] = nfa.newNode()
+		_, nodes[i].endpoint = b.accept[id]
+	}
+
+	nfa.start = nodes[ids[b.start[0]]]
+	nfa.start.linkscnt++
+
+	for _, edge := range b.edges {
+		from, to := ids[edge.from], ids[edge.to]
+		if edge.empty {
+			nodes[from].linkEmpty(nodes[to])
+		} else {
+			nodes[from].link(edge.r, nodes[to])
+		}
+	}
+
+	return nfa, nil
+}
@@ -0,0 +1,71 @@
+package formallang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNFABuilder(t *testing.T) {
+	abc := map[rune]struct{}{'a': {}, 'b': {}}
+
+	// q1 is declared by edge only
+	nfa, err := NewNFABuilder(abc).State("q0").Start().Accept().Edge("q0", 'a', "q1").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nfa.nodes) != 2 {
+		t.Errorf("got %v states, want 2", len(nfa.nodes))
+	}
+	for word, want := range map[string]bool{"": true, "a": false, "b": false} {
+		if got := nfa.Accepts(word); got != want {
+			t.Errorf("%q accepted %v, want %v", word, got, want)
+		}
+	}
+
+	// state used by edge before its declaration keeps Accept
+	nfa, err = NewNFABuilder(abc).
+		State("q0").Start().
+		Edge("q0", 'a', "q1").
+		EmptyEdge("q1", "q2").
+		State("q1").
+		State("q2").Accept().
+		Edge("q2", 'b', "q2").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, want := range map[string]bool{"": false, "a": true, "abb": true, "b": false} {
+		if got := nfa.Accepts(word); got != want {
+			t.Errorf("%q accepted %v, want %v", word, got, want)
+		}
+	}
+}
+
+func TestNFABuilderErrors(t *testing.T) {
+	abc := map[rune]struct{}{'a': {}}
+
+	tests := []struct {
+		name    string
+		builder *NFABuilder
+		want    string
+	}{
+		{"missing start", NewNFABuilder(abc).State("q0").Accept(), "start state is missing"},
+		{"several starts", NewNFABuilder(abc).State("q0").Start().State("q1").Start(), "several start states"},
+		{"unknown symbol", NewNFABuilder(abc).State("q0").Start().Edge("q0", 'b', "q0"), "is not in alphabet"},
+		{"duplicate state", NewNFABuilder(abc).State("q0").Start().State("q0"), "duplicate state"},
+		{"start before state", NewNFABuilder(abc).Start().State("q0"), "start is set before any state"},
+		{"accept before state", NewNFABuilder(abc).Accept().State("q0").Start(), "accept is set before any state"},
+	}
+
+	for _, test := range tests {
+		_, err := test.builder.Build()
+		if err == nil {
+			t.Errorf("%v: expected error %q", test.name, test.want)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got error %q, want %q", test.name, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package formallang
+
+func regExpNodeKey(node regExpNode) string {
+	return node.ToString(lowPriority, SyntaxExtended)
+}
+
+func (node regExpNodeEmptyRune) Optimize() regExpNode { return node }
+func (node regExpNodeEmptySet) Optimize() regExpNode  { return node }
+func (node regExpNodeRune) Optimize() regExpNode      { return node }
+
+func (add regExpNodeAdd) Optimize() regExpNode {
+	nodes := make([]regExpNode, 0, len(add.Next))
+	for _, next := range add.Next {
+		next = next.Optimize()
+
+		if inner, ok := next.(regExpNodeAdd); ok {
+			nodes = append(nodes, inner.Next...)
+		} else {
+			nodes = append(nodes, next)
+		}
+	}
+
+	hasEmpty := false
+	hasNullable := false
+	used := make(map[string]struct{})
+	res := make([]regExpNode, 0, len(nodes))
+	for _, next := range nodes {
+		if _, ok := next.(regExpNodeEmptySet); ok {
+			continue
+		}
+		if _, ok := next.(regExpNodeEmptyRune); ok {
+			hasEmpty = true
+			continue
+		}
+		if _, ok := next.(regExpNodeClini); ok {
+			hasNullable = true
+		}
+
+		key := regExpNodeKey(next)
+		if _, ok := used[key]; ok {
+			continue
+		}
+
+		used[key] = struct{}{}
+		res = append(res, next)
+	}
+
+	// x + x* = x*
+	starred := make(map[string]struct{})
+	for _, next := range res {
+		if clini, ok := next.(regExpNodeClini); ok {
+			starred[regExpNodeKey(clini.Next)] = struct{}{}
+		}
+	}
+	if len(starred) > 0 {
+		absorbed := res[:0]
+		for _, next := range res {
+			if _, ok := starred[regExpNodeKey(next)]; !ok {
+				absorbed = append(absorbed, next)
+			}
+		}
+		res = absorbed
+	}
+
+	// 1 + xx* = x*
+	if hasEmpty && !hasNullable {
+		for i, next := range res {
+			if clini, ok := plusToClini(next); ok {
+				res[i] = clini
+				hasNullable = true
+				break
+			}
+		}
+	}
+
+	if hasEmpty && !hasNullable {
+		res = append(res, regExpNodeEmptyRune{})
+	}
+
+	switch len(res) {
+	case 0:
+		return regExpNodeEmptySet{}
+	case 1:
+		return res[0]
+	}
+
+	return regExpNodeAdd{res}
+}
+
+// plusToClini - recognizes xx* and x*x and returns x*
+func plusToClini(node regExpNode) (regExpNode, bool) {
+	mul, ok := node.(regExpNodeMul)
+	if !ok || len(mul.Next) < 2 {
+		return nil, false
+	}
+
+	first, last := mul.Next[0], mul.Next[len(mul.Next)-1]
+
+	if clini, ok := last.(regExpNodeClini); ok {
+		if regExpNodeKey(clini.Next) == regExpNodeKey(mulOf(mul.Next[:len(mul.Next)-1])) {
+			return clini, true
+		}
+	}
+
+	if clini, ok := first.(regExpNodeClini); ok {
+		if regExpNodeKey(clini.Next) == regExpNodeKey(mulOf(mul.Next[1:])) {
+			return clini, true
+		}
+	}
+
+	return nil, false
+}
+
+func mulOf(nodes []regExpNode) regExpNode {
+	switch len(nodes) {
+	case 0:
+		return regExpNodeEmptyRune{}
+	case 1:
+		return nodes[0]
+	}
+
+	return regExpNodeMul{nodes}
+}
+
+func (mul regExpNodeMul) Optimize() regExpNode {
+	nodes := make([]regExpNode, 0, len(mul.Next))
+	for _, next := range mul.Next {
+		next = next.Optimize()
+
+		switch next := next.(type) {
+		case regExpNodeEmptySet:
+			return next
+		case regExpNodeEmptyRune:
+		case regExpNodeMul:
+			nodes = append(nodes, next.Next...)
+		default:
+			nodes = append(nodes, next)
+		}
+	}
+
+	// x*x* = x*
+	res := make([]regExpNode, 0, len(nodes))
+	for _, next := range nodes {
+		if len(res) > 0 {
+			_, prevClini := res[len(res)-1].(regExpNodeClini)
+			_, currClini := next.(regExpNodeClini)
+
+			if prevClini && currClini && regExpNodeKey(res[len(res)-1]) == regExpNodeKey(next) {
+				continue
+			}
+		}
+
+		res = append(res, next)
+	}
+
+	return mulOf(res)
+}
+
+func (clini regExpNodeClini) Optimize() regExpNode {
+	next := clini.Next.Optimize()
+
+	switch inner := next.(type) {
+	case regExpNodeEmptySet:
+		return regExpNodeEmptyRune{}
+	case regExpNodeEmptyRune:
+		return inner
+	case regExpNodeClini:
+		return inner
+	case regExpNodeAdd:
+		// (1 + x + y*)* = (x + y)*
+		nodes := make([]regExpNode, 0, len(inner.Next))
+		for _, node := range inner.Next {
+			if _, ok := node.(regExpNodeEmptyRune); ok {
+				continue
+			}
+			if nodeClini, ok := node.(regExpNodeClini); ok {
+				node = nodeClini.Next
+			}
+
+			nodes = append(nodes, node)
+		}
+
+		next = regExpNodeAdd{nodes}.Optimize()
+		switch next := next.(type) {
+		case regExpNodeEmptySet:
+			return regExpNodeEmptyRune{}
+		case regExpNodeEmptyRune:
+			return next
+		case regExpNodeClini:
+			return next
+		}
+	}
+
+	return regExpNodeClini{next}
+}
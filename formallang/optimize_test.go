@@ -0,0 +1,66 @@
+package formallang
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// randomRegExp - random classic expression over a, b and 1
+func randomRegExp(rng *rand.Rand, depth int) string {
+	if depth == 0 || rng.Intn(4) == 0 {
+		return string("ab1"[rng.Intn(3)])
+	}
+
+	switch rng.Intn(3) {
+	case 0:
+		return "(" + randomRegExp(rng, depth-1) + " + " + randomRegExp(rng, depth-1) + ")"
+	case 1:
+		return randomRegExp(rng, depth-1) + randomRegExp(rng, depth-1)
+	}
+
+	return "(" + randomRegExp(rng, depth-1) + ")*"
+}
+
+func minimalDOT(t *testing.T, reg *RegExp) string {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	if err := CDFAfromDFA(DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())).Minimise().WriteDOT(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestOptimizeSimplifies(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"a + a", "a"},
+		{"(a*)*", "a*"},
+		{"1 + aa*", "a*"},
+		{"a + a*", "a*"},
+		{"a*a*", "a*"},
+		{"(1 + a + b*)*", "(a + b)*"},
+		{"a1b", "ab"},
+	}
+
+	for _, test := range tests {
+		if got := mustRegExp(t, test.input).Optimize().ToString(); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestOptimizeKeepsLanguage(t *testing.T) {
+	for _, input := range regExpInputs(t, 300) {
+		reg := mustRegExp(t, input)
+		reg.abc = map[rune]struct{}{'a': {}, 'b': {}}
+
+		optimized := reg.Optimize()
+		if before, after := minimalDOT(t, reg), minimalDOT(t, optimized); before != after {
+			t.Errorf("%v optimized to %v: minimal CDFA differs\n%v\n%v", input, optimized.ToString(), before, after)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package formallang
+
+// partition - refinable partition of states 0..n-1 into blocks
+type partition struct {
+	elems   []int
+	loc     []int
+	blockOf []int
+	first   []int
+	end     []int
+	marked  []int
+	touched []int
+}
+
+func newPartition(n int) *partition {
+	p := &partition{
+		elems:   make([]int, n),
+		loc:     make([]int, n),
+		blockOf: make([]int, n),
+	}
+
+	for i := range p.elems {
+		p.elems[i] = i
+		p.loc[i] = i
+	}
+
+	if n > 0 {
+		p.first = append(p.first, 0)
+		p.end = append(p.end, n)
+		p.marked = append(p.marked, 0)
+	}
+
+	return p
+}
+
+func (p *partition) size(block int) int {
+	return p.end[block] - p.first[block]
+}
+
+func (p *partition) block(block int) []int {
+	return p.elems[p.first[block]:p.end[block]]
+}
+
+func (p *partition) mark(state int) {
+	block := p.blockOf[state]
+	i, j := p.loc[state], p.first[block]+p.marked[block]
+	if i < j {
+		return
+	}
+
+	p.elems[i], p.elems[j] = p.elems[j], p.elems[i]
+	p.loc[p.elems[i]], p.loc[p.elems[j]] = i, j
+
+	if p.marked[block] == 0 {
+		p.touched = append(p.touched, block)
+	}
+	p.marked[block]++
+}
+
+// split - splits every touched block into marked and unmarked parts, calls onSplit with old and new block
+func (p *partition) split(onSplit func(block, created int)) {
+	for _, block := range p.touched {
+		marked := p.marked[block]
+		p.marked[block] = 0
+
+		if marked == p.size(block) {
+			continue
+		}
+
+		created := len(p.first)
+		p.first = append(p.first, p.first[block])
+		p.end = append(p.end, p.first[block]+marked)
+		p.marked = append(p.marked, 0)
+		p.first[block] += marked
+
+		for _, state := range p.block(created) {
+			p.blockOf[state] = created
+		}
+
+		onSplit(block, created)
+	}
+
+	p.touched = p.touched[:0]
+}
@@ -0,0 +1,43 @@
+package formallang
+
+// MinLenWithPrefixPower - returns length of the shortest word of language, that starts with r repeated k times,
+// false if there is no such word or k is negative
+func (dfa *DFA) MinLenWithPrefixPower(r rune, k int) (int, bool) {
+	if k < 0 {
+		return 0, false
+	}
+
+	curr := dfa.start
+	for i := 0; i < k && curr != nil; i++ {
+		curr = curr.step(r)
+	}
+
+	if curr == nil {
+		return 0, false
+	}
+
+	dist := map[*dfanode]int{curr: 0}
+
+	var tasks queue
+	tasks.Push(curr)
+
+	for tasks.Size() > 0 {
+		from := tasks.Top().(*dfanode)
+		tasks.Pop()
+
+		if from.endpoint {
+			return k + dist[from], true
+		}
+
+		for _, to := range from.next {
+			if _, ok := dist[to]; ok {
+				continue
+			}
+
+			dist[to] = dist[from] + 1
+			tasks.Push(to)
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,37 @@
+package formallang
+
+import "testing"
+
+func TestMinLenWithPrefixPower(t *testing.T) {
+	tests := []struct {
+		rpn  string
+		r    rune
+		k    int
+		want int
+		ok   bool
+	}{
+		{"ab+*", 'a', 0, 0, true},
+		{"ab+*", 'a', 3, 3, true},
+		{"ab.", 'a', 1, 2, true},
+		{"ab.", 'a', 0, 2, true},
+		{"ab.", 'a', 2, 0, false},
+		{"aa.*b.", 'a', 3, 5, true},
+		{"ab.c+", 'c', 1, 1, true},
+		{"ab.", 'c', 1, 0, false},
+		{"ab.", 'c', 0, 2, true},
+		{"ab+*", 'a', -1, 0, false},
+	}
+
+	for _, test := range tests {
+		reg, err := RegExpFromRPN(test.rpn)
+		if err != nil {
+			t.Fatalf("%v: %v", test.rpn, err)
+		}
+
+		dfa := DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())
+		got, ok := dfa.MinLenWithPrefixPower(test.r, test.k)
+		if got != test.want || ok != test.ok {
+			t.Errorf("%v, %c^%v: got %v, %v, want %v, %v", test.rpn, test.r, test.k, got, ok, test.want, test.ok)
+		}
+	}
+}
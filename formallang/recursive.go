@@ -1,20 +1,55 @@
 package formallang
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	//"reflect"
 )
 
-func createRegExpNodes(tokens []Token) (regExpNode, error) {
+// tokenError - parsing failed on token with index idx, idx equal to len(tokens) means end of input
+type tokenError struct {
+	idx    int
+	column int
+}
+
+func (err tokenError) Error() string {
+	if err.column > 0 {
+		return fmt.Sprintf("can't parse on column %v", err.column)
+	}
+
+	return fmt.Sprintf("can't parse on index %v", err.idx)
+}
+
+func parseError(tokens []Token, idx int) error {
+	err := tokenError{idx: idx}
+	if idx < len(tokens) && tokens[idx].Pos > 0 {
+		err.column = tokens[idx].Pos
+	}
+	if idx >= len(tokens) && len(tokens) > 0 && tokens[len(tokens)-1].Pos > 0 {
+		err.column = tokens[len(tokens)-1].Pos + 1
+	}
+
+	return err
+}
+
+// failedOn - checks if rule failed right on token idx, so it just doesn't start there,
+// errors after consumed tokens are reported as they are
+func failedOn(err error, idx int) bool {
+	var tokenErr tokenError
+	return errors.As(err, &tokenErr) && tokenErr.idx == idx
+}
+
+func createRegExpNodes(tokens []Token, syntax Syntax) (regExpNode, error) {
 	var start = 0
 
-	res, err := recursiveGetSum(tokens, &start)
+	res, err := recursiveGetSum(tokens, &start, syntax)
 	if err != nil {
 		return nil, err
 	}
 
 	if start != len(tokens) {
-		return nil, fmt.Errorf("can't parse on index %v", start)
+		return nil, parseError(tokens, start)
 	}
 
 	return res, err
@@ -22,17 +57,22 @@ func createRegExpNodes(tokens []Token) (regExpNode, error) {
 
 func recursiveGetRune(tokens []Token, idx *int) (regExpNode, error) {
 	if *idx >= len(tokens) {
-		return nil, fmt.Errorf("can't parse on index %v", *idx)
+		return nil, parseError(tokens, *idx)
 	}
 
 	var res regExpNode
 	content := tokens[*idx]
 	if content.Servicable {
-		if content.Symb != '1' {
-			return nil, fmt.Errorf("can't parse on index %v", *idx)
+		switch content.Symb {
+		case '1':
+			res = regExpNodeEmptyRune{}
+		case '0':
+			res = regExpNodeEmptySet{}
+		case '.':
+			res = newRegExpNodeClass(nil, true)
+		default:
+			return nil, parseError(tokens, *idx)
 		}
-
-		res = regExpNodeEmptyRune{}
 	} else {
 		res = regExpNodeRune{content.Symb}
 	}
@@ -41,30 +81,44 @@ func recursiveGetRune(tokens []Token, idx *int) (regExpNode, error) {
 	return res, nil
 }
 
-func recursiveGetBrasClini(tokens []Token, idx *int) (regExpNode, error) {
+func recursiveGetBrasClini(tokens []Token, idx *int, syntax Syntax) (regExpNode, error) {
 	if *idx >= len(tokens) {
-		return nil, fmt.Errorf("can't parse on index %v", *idx)
+		return nil, parseError(tokens, *idx)
 	}
 	start := *idx
 
+	var err error
 	for {
 		var res regExpNode
-		var err error
-		if tokens[*idx].Servicable && tokens[*idx].Symb == '(' {
+		if tokens[*idx].Servicable && tokens[*idx].Symb == '~' {
 			(*idx)++
 
-			res, err = recursiveGetSum(tokens, idx)
+			res, err = recursiveGetBrasClini(tokens, idx, syntax)
 			if err != nil {
 				break
 			}
 
-			if *idx >= len(tokens) {
+			return regExpNodeComplement{res}, nil
+		}
+
+		if tokens[*idx].Servicable && tokens[*idx].Symb == '(' {
+			(*idx)++
+
+			res, err = recursiveGetSum(tokens, idx, syntax)
+			if err != nil {
 				break
 			}
-			if !(tokens[*idx].Servicable && tokens[*idx].Symb == ')') {
+
+			if *idx >= len(tokens) || !(tokens[*idx].Servicable && tokens[*idx].Symb == ')') {
+				err = parseError(tokens, *idx)
 				break
 			}
 			(*idx)++
+		} else if tokens[*idx].Servicable && tokens[*idx].Symb == '[' {
+			res, err = recursiveGetClass(tokens, idx)
+			if err != nil {
+				break
+			}
 		} else {
 			res, err = recursiveGetRune(tokens, idx)
 			if err != nil {
@@ -72,27 +126,29 @@ func recursiveGetBrasClini(tokens []Token, idx *int) (regExpNode, error) {
 			}
 		}
 
-		for (*idx) < len(tokens) && tokens[*idx].Servicable && tokens[*idx].Symb == '*' {
-			res = regExpNodeClini{res}
-			(*idx)++
+		res, err = recursiveGetPostfix(tokens, idx, syntax, res)
+		if err != nil {
+			break
 		}
 
 		return res, nil
 	}
 
 	*idx = start
-	return nil, fmt.Errorf("can't parse on index %v", *idx)
+	return nil, err
 }
 
-func recursiveGetSum(tokens []Token, idx *int) (regExpNode, error) {
+func recursiveGetSum(tokens []Token, idx *int, syntax Syntax) (regExpNode, error) {
 	if *idx >= len(tokens) {
-		return nil, fmt.Errorf("can't parse on index %v", *idx)
+		return nil, parseError(tokens, *idx)
 	}
 	start := *idx
 
+	var err error
 loop:
 	for {
-		res, err := recursiveGetMul(tokens, idx)
+		var res regExpNode
+		res, err = recursiveGetIntersect(tokens, idx, syntax)
 		if err != nil {
 			break
 		}
@@ -100,9 +156,11 @@ loop:
 		nodes := make([]regExpNode, 1)
 		nodes[0] = res
 
-		for *idx < len(tokens) && tokens[*idx].Symb == '+' {
+		for *idx < len(tokens) && tokens[*idx].Servicable && tokens[*idx].Symb == syntax.alternation() {
 			(*idx)++
-			buf, err := recursiveGetMul(tokens, idx)
+
+			var buf regExpNode
+			buf, err = recursiveGetIntersect(tokens, idx, syntax)
 			if err != nil {
 				break loop
 			}
@@ -111,47 +169,230 @@ loop:
 		}
 
 		if len(nodes) > 1 {
-			return regExpNodeAdd{nodes}, nil	
+			return regExpNodeAdd{nodes}, nil
 		}
 
 		return nodes[0], nil
 	}
 
 	*idx = start
-	return nil, fmt.Errorf("can't parse on index %v", *idx)
+	return nil, err
 }
 
-func recursiveGetMul(tokens []Token, idx *int) (regExpNode, error) {
+func recursiveGetIntersect(tokens []Token, idx *int, syntax Syntax) (regExpNode, error) {
 	if *idx >= len(tokens) {
-		return nil, fmt.Errorf("can't parse on index %v", *idx)
+		return nil, parseError(tokens, *idx)
 	}
 	start := *idx
 
+	var err error
+loop:
 	for {
-		res, err := recursiveGetBrasClini(tokens, idx)
+		var res regExpNode
+		res, err = recursiveGetMul(tokens, idx, syntax)
 		if err != nil {
 			break
 		}
-		
+
 		nodes := make([]regExpNode, 1)
 		nodes[0] = res
 
-		for {
-			buf, err := recursiveGetBrasClini(tokens, idx)
+		for *idx < len(tokens) && tokens[*idx].Servicable && tokens[*idx].Symb == '&' {
+			(*idx)++
+
+			var buf regExpNode
+			buf, err = recursiveGetMul(tokens, idx, syntax)
 			if err != nil {
+				break loop
+			}
+
+			nodes = append(nodes, buf)
+		}
+
+		if len(nodes) > 1 {
+			return regExpNodeIntersect{nodes}, nil
+		}
+
+		return nodes[0], nil
+	}
+
+	*idx = start
+	return nil, err
+}
+
+func recursiveGetMul(tokens []Token, idx *int, syntax Syntax) (regExpNode, error) {
+	if *idx >= len(tokens) {
+		return nil, parseError(tokens, *idx)
+	}
+	start := *idx
+
+	var err error
+loop:
+	for {
+		var res regExpNode
+		res, err = recursiveGetBrasClini(tokens, idx, syntax)
+		if err != nil {
+			break
+		}
+
+		nodes := make([]regExpNode, 1)
+		nodes[0] = res
+
+		for {
+			next := *idx
+
+			var buf regExpNode
+			buf, err = recursiveGetBrasClini(tokens, idx, syntax)
+			if failedOn(err, next) {
 				break
 			}
-			
+			if err != nil {
+				break loop
+			}
+
 			nodes = append(nodes, buf)
 		}
 
 		if len(nodes) > 1 {
-			return regExpNodeMul{nodes}, nil	
+			return regExpNodeMul{nodes}, nil
 		}
 
 		return nodes[0], nil
 	}
 
 	*idx = start
-	return nil, fmt.Errorf("can't parse on index %v", *idx)
+	return nil, err
+}
+
+func recursiveGetPostfix(tokens []Token, idx *int, syntax Syntax, res regExpNode) (regExpNode, error) {
+	for *idx < len(tokens) && tokens[*idx].Servicable {
+		switch symb := tokens[*idx].Symb; {
+		case symb == '*':
+			res = regExpNodeClini{res}
+		case syntax == SyntaxExtended && symb == '+':
+			res = regExpNodePlus{res}
+		case syntax == SyntaxExtended && symb == '?':
+			res = regExpNodeOptional{res}
+		case syntax == SyntaxExtended && symb == '{':
+			repeat, err := recursiveGetRepeat(tokens, idx, res)
+			if err != nil {
+				return nil, err
+			}
+
+			res = repeat
+			continue
+		default:
+			return res, nil
+		}
+
+		(*idx)++
+	}
+
+	return res, nil
+}
+
+// recursiveGetRepeat - parses {n}, {n,} and {n,m}, digits inside braces are taken as is
+func recursiveGetRepeat(tokens []Token, idx *int, res regExpNode) (regExpNode, error) {
+	start := *idx
+	(*idx)++
+
+	readNumber := func() (int, bool) {
+		begin := *idx
+		digits := make([]rune, 0)
+		for *idx < len(tokens) && '0' <= tokens[*idx].Symb && tokens[*idx].Symb <= '9' {
+			digits = append(digits, tokens[*idx].Symb)
+			(*idx)++
+		}
+
+		if len(digits) == 0 {
+			*idx = begin
+			return 0, false
+		}
+
+		num, err := strconv.Atoi(string(digits))
+		return num, err == nil
+	}
+
+	isSymb := func(r rune) bool {
+		return *idx < len(tokens) && tokens[*idx].Symb == r
+	}
+
+	minCount, ok := readNumber()
+	if !ok {
+		return nil, parseError(tokens, *idx)
+	}
+
+	maxCount := minCount
+	if isSymb(',') && !tokens[*idx].Servicable {
+		(*idx)++
+
+		maxCount = -1
+		if num, ok := readNumber(); ok {
+			maxCount = num
+		}
+	}
+
+	if !(isSymb('}') && tokens[*idx].Servicable) {
+		return nil, parseError(tokens, *idx)
+	}
+	if maxCount != -1 && maxCount < minCount {
+		return nil, parseError(tokens, start)
+	}
+
+	(*idx)++
+	return regExpNodeRepeat{res, minCount, maxCount}, nil
+}
+
+func recursiveGetClass(tokens []Token, idx *int) (regExpNode, error) {
+	runes, negated, err := classRunes(tokens, idx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRegExpNodeClass(runes, negated), nil
+}
+
+// classRunes - parses [abc], [^abc] and ranges [a-z], - is literal if it can't be a range
+func classRunes(tokens []Token, idx *int) ([]rune, bool, error) {
+	(*idx)++
+
+	isService := func(i int, r rune) bool {
+		return i < len(tokens) && tokens[i].Servicable && tokens[i].Symb == r
+	}
+
+	negated := isService(*idx, '^')
+	if negated {
+		(*idx)++
+	}
+
+	runes := make([]rune, 0)
+	for !isService(*idx, ']') {
+		if *idx >= len(tokens) {
+			return nil, false, parseError(tokens, *idx)
+		}
+
+		from := tokens[*idx].Symb
+		if tokens[*idx].Servicable && from != '-' {
+			return nil, false, parseError(tokens, *idx)
+		}
+		(*idx)++
+
+		if !isService(*idx, '-') || *idx+1 >= len(tokens) || tokens[*idx+1].Servicable {
+			runes = append(runes, from)
+			continue
+		}
+
+		to := tokens[*idx+1].Symb
+		if to < from {
+			return nil, false, parseError(tokens, *idx+1)
+		}
+
+		for r := from; r <= to; r++ {
+			runes = append(runes, r)
+		}
+		*idx += 2
+	}
+
+	(*idx)++
+	return runes, negated, nil
 }
@@ -0,0 +1,40 @@
+package formallang
+
+import "testing"
+
+func TestParseErrorColumn(t *testing.T) {
+	tests := []struct {
+		str, want string
+	}{
+		// end of input
+		{"a+", "can't parse on column 3"},
+		{"(a", "can't parse on column 3"},
+		{"a + (b", "can't parse on column 7"},
+		{"~", "can't parse on column 2"},
+		{"[ab", "can't parse on column 4"},
+		// offending token
+		{"b(a", "can't parse on column 4"},
+		{"a)", "can't parse on column 2"},
+		{"()", "can't parse on column 2"},
+		{")", "can't parse on column 1"},
+		{"ab & + c", "can't parse on column 6"},
+		{"[z-a]", "can't parse on column 4"},
+	}
+
+	for _, test := range tests {
+		tokens, err := Tokenize(test.str)
+		if err != nil {
+			t.Fatalf("%q: %v", test.str, err)
+		}
+
+		_, err = RegExpFromTokens(tokens)
+		if err == nil {
+			t.Errorf("%q: expected error %q", test.str, test.want)
+			continue
+		}
+
+		if err.Error() != test.want {
+			t.Errorf("%q: got error %q, want %q", test.str, err, test.want)
+		}
+	}
+}
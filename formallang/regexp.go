@@ -1,43 +1,80 @@
 package formallang
 
+import "maps"
+
 // RegExp - basic struct for regular expression
 type RegExp struct {
-	abc  map[rune]struct{}
-	tree regExpNode
+	abc    map[rune]struct{}
+	tree   regExpNode
+	syntax Syntax
 }
 
 // Token - basic struct that string must be sliced
 type Token struct {
 	Symb       rune
 	Servicable bool
+	// Pos - column of token in source string counted from 1, 0 if unknown
+	Pos int
 }
 
 // ToString - convert to
 func (reg RegExp) ToString() string {
-	return reg.tree.ToString(lowPriority)
+	return reg.tree.ToString(lowPriority, reg.syntax)
 }
 
-// Optimize - creates new optimized RegExp 
+// Optimize - creates new optimized RegExp
 func (reg RegExp) Optimize() *RegExp {
-	panic("implement me")
+	return &RegExp{
+		abc:    maps.Clone(reg.abc),
+		tree:   reg.tree.Optimize(),
+		syntax: reg.syntax,
+	}
 }
 
-// RegExpFromTokens - construct regular expression from token slice
-func RegExpFromTokens(tokens []Token) (*RegExp, error) {
+// tokensAlphabet - symbols of tokens, ranges of classes are expanded and repetition counts are skipped
+func tokensAlphabet(tokens []Token, syntax Syntax) map[rune]struct{} {
 	dict := make(map[rune]struct{})
 
-	for _, token := range tokens {
-		if !token.Servicable {
+	inBraces := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.Servicable {
+			switch token.Symb {
+			case '{':
+				inBraces = syntax == SyntaxExtended
+			case '}':
+				inBraces = false
+			case '[':
+				idx := i
+				runes, _, err := classRunes(tokens, &idx)
+				if err != nil {
+					continue
+				}
+
+				for _, r := range runes {
+					dict[r] = struct{}{}
+				}
+				i = idx - 1
+			}
+			continue
+		}
+
+		if !inBraces {
 			dict[token.Symb] = struct{}{}
 		}
 	}
 
-	return RegExpFromTokensWithDict(tokens, dict)
+	return dict
+}
+
+// RegExpFromTokens - construct regular expression from token slice
+func RegExpFromTokens(tokens []Token) (*RegExp, error) {
+	return RegExpFromTokensWithDict(tokens, tokensAlphabet(tokens, SyntaxClassic))
 }
 
 // RegExpFromTokensWithDict - construct regular expression from string with given alphabet
 func RegExpFromTokensWithDict(tokens []Token, abc map[rune]struct{}) (*RegExp, error) {
-	regexpnode, err := createRegExpNodes(tokens)
+	regexpnode, err := createRegExpNodes(tokens, SyntaxClassic)
 
 	res := &RegExp{
 		abc:  abc,
@@ -47,6 +84,19 @@ func RegExpFromTokensWithDict(tokens []Token, abc map[rune]struct{}) (*RegExp, e
 	return res, err
 }
 
+// RegExpFromTokensWithSyntax - construct regular expression of given syntax from token slice
+func RegExpFromTokensWithSyntax(tokens []Token, syntax Syntax) (*RegExp, error) {
+	regexpnode, err := createRegExpNodes(tokens, syntax)
+
+	res := &RegExp{
+		abc:    tokensAlphabet(tokens, syntax),
+		tree:   regexpnode,
+		syntax: syntax,
+	}
+
+	return res, err
+}
+
 // Test - for test
 func Test(tokens []Token) string {
 	expr, err := RegExpFromTokens(tokens)
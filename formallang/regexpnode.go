@@ -6,15 +6,22 @@ import (
 )
 
 type regExpNode interface {
-	ToString(priority int) string
+	ToString(priority int, syntax Syntax) string
 	Priority() int
 	ToSubNFA(nfa *NFA, begin, end *nfanode)
+	Optimize() regExpNode
+	Glushkov(g *glushkovBuilder) glushkovSets
+	Nullable() bool
+	Derivative(r rune) regExpNode
+	PartialDerivative(r rune) regExpNodeSet
 }
 
 const (
 	lowPriority = iota
 	addPriority
+	intersectPriority
 	mulPriority
+	complementPriority
 	cliniPriority
 	runePriority
 	emptyRunepriority
@@ -23,18 +30,37 @@ const (
 
 type regExpNodeEmptyRune struct{}
 
-func (regExpNodeEmptyRune) Priority() int       { return hightPriority }
-func (regExpNodeEmptyRune) ToString(int) string { return "1" }
+func (regExpNodeEmptyRune) Priority() int               { return hightPriority }
+func (regExpNodeEmptyRune) ToString(int, Syntax) string { return "1" }
 func (regExpNodeEmptyRune) ToSubNFA(nfa *NFA, begin, end *nfanode) {
-	begin.link(EmptyRune, end)
+	begin.linkEmpty(end)
 }
 
+type regExpNodeEmptySet struct{}
+
+func (regExpNodeEmptySet) Priority() int { return hightPriority }
+
+// ToString - classic syntax has no empty set symbol, so it is written as empty class
+func (regExpNodeEmptySet) ToString(_ int, syntax Syntax) string {
+	if syntax == SyntaxExtended {
+		return "0"
+	}
+
+	return "[]"
+}
+func (regExpNodeEmptySet) ToSubNFA(nfa *NFA, begin, end *nfanode) {}
+
 type regExpNodeRune struct {
 	r rune
 }
 
-func (regExpNodeRune) Priority() int         { return runePriority }
-func (r regExpNodeRune) ToString(int) string { return fmt.Sprintf("%c", r.r) }
+func (regExpNodeRune) Priority() int { return runePriority }
+func (r regExpNodeRune) ToString(_ int, syntax Syntax) string {
+	if isServiceRune(r.r, syntax) || r.r == escapeRune {
+		return fmt.Sprintf("%c%c", escapeRune, r.r)
+	}
+	return fmt.Sprintf("%c", r.r)
+}
 func (r regExpNodeRune) ToSubNFA(nfa *NFA, begin, end *nfanode) {
 	begin.link(r.r, end)
 }
@@ -44,7 +70,7 @@ type regExpNodeAdd struct {
 }
 
 func (regExpNodeAdd) Priority() int { return addPriority }
-func (add regExpNodeAdd) ToString(priority int) string {
+func (add regExpNodeAdd) ToString(priority int, syntax Syntax) string {
 	prior := add.Priority()
 
 	var builder strings.Builder
@@ -53,11 +79,11 @@ func (add regExpNodeAdd) ToString(priority int) string {
 		builder.WriteRune('(')
 	}
 
-	builder.WriteString(add.Next[0].ToString(prior))
+	builder.WriteString(add.Next[0].ToString(prior, syntax))
 
 	for _, next := range add.Next[1:] {
-		builder.WriteString(" + ")
-		builder.WriteString(next.ToString(prior))
+		fmt.Fprintf(&builder, " %c ", syntax.alternation())
+		builder.WriteString(next.ToString(prior, syntax))
 	}
 
 	if prior < priority {
@@ -70,9 +96,9 @@ func (add regExpNodeAdd) ToSubNFA(nfa *NFA, begin, end *nfanode) {
 	for _, regexprnode := range add.Next {
 		bufBegin, bufEnd := nfa.newNode(), nfa.newNode()
 		regexprnode.ToSubNFA(nfa, bufBegin, bufEnd)
-		
-		begin.link(EmptyRune, bufBegin)
-		bufEnd.link(EmptyRune, end)
+
+		begin.linkEmpty(bufBegin)
+		bufEnd.linkEmpty(end)
 	}
 }
 
@@ -81,7 +107,7 @@ type regExpNodeMul struct {
 }
 
 func (regExpNodeMul) Priority() int { return mulPriority }
-func (mul regExpNodeMul) ToString(priority int) string {
+func (mul regExpNodeMul) ToString(priority int, syntax Syntax) string {
 	prior := mul.Priority()
 
 	var builder strings.Builder
@@ -90,10 +116,10 @@ func (mul regExpNodeMul) ToString(priority int) string {
 		builder.WriteRune('(')
 	}
 
-	builder.WriteString(mul.Next[0].ToString(prior))
+	builder.WriteString(mul.Next[0].ToString(prior, syntax))
 
 	for _, next := range mul.Next[1:] {
-		builder.WriteString(next.ToString(prior))
+		builder.WriteString(next.ToString(prior, syntax))
 	}
 
 	if prior < priority {
@@ -121,11 +147,13 @@ type regExpNodeClini struct {
 }
 
 func (regExpNodeClini) Priority() int { return cliniPriority }
-func (clini regExpNodeClini) ToString(priority int) string {
+func (clini regExpNodeClini) ToString(priority int, syntax Syntax) string {
 	prior := clini.Priority()
-	return fmt.Sprintf("%v*", clini.Next.ToString(prior))
+	return fmt.Sprintf("%v*", clini.Next.ToString(prior, syntax))
 }
 func (clini regExpNodeClini) ToSubNFA(nfa *NFA, begin, end *nfanode) {
-	clini.Next.ToSubNFA(nfa, begin, begin)
-	begin.link(EmptyRune, end)
-}
\ No newline at end of file
+	loop := nfa.newNode()
+	clini.Next.ToSubNFA(nfa, loop, loop)
+	begin.linkEmpty(loop)
+	loop.linkEmpty(end)
+}
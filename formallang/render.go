@@ -0,0 +1,93 @@
+package formallang
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/goccy/go-graphviz"
+)
+
+// Format - output format of Render
+type Format = graphviz.Format
+
+const (
+	// FormatDOT - graphviz DOT source without layout
+	FormatDOT = graphviz.XDOT
+	// FormatSVG - svg image
+	FormatSVG = graphviz.SVG
+	// FormatPNG - png image
+	FormatPNG = graphviz.PNG
+	// FormatJPG - jpg image
+	FormatJPG = graphviz.JPG
+)
+
+type dotWriter interface {
+	WriteDOT(w io.Writer) error
+}
+
+// render - renders DOT source of automaton with graphviz
+func render(automaton dotWriter, w io.Writer, format Format) (err error) {
+	switch format {
+	case FormatDOT:
+		return automaton.WriteDOT(w)
+	case FormatSVG, FormatPNG, FormatJPG:
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	source := &bytes.Buffer{}
+	if err := automaton.WriteDOT(source); err != nil {
+		return err
+	}
+
+	graph, err := graphviz.ParseBytes(source.Bytes())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := graph.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	g := graphviz.New()
+	defer func() {
+		if closeErr := g.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return g.Render(graph, format, w)
+}
+
+// dump - renders automaton into file
+func dump(automaton dotWriter, filename string, format Format) (err error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return render(automaton, file, format)
+}
+
+// Render - renders NFA in given format
+func (nfa *NFA) Render(w io.Writer, format Format) error {
+	return render(nfa, w, format)
+}
+
+// Render - renders DFA in given format
+func (dfa *DFA) Render(w io.Writer, format Format) error {
+	return render(dfa, w, format)
+}
+
+// Render - renders CDFA in given format
+func (cdfa *CDFA) Render(w io.Writer, format Format) error {
+	return render(cdfa, w, format)
+}
@@ -0,0 +1,114 @@
+package formallang
+
+import (
+	"fmt"
+	"unicode"
+)
+
+const (
+	rpnAdd   = rune('+')
+	rpnMul   = rune('.')
+	rpnClini = rune('*')
+	rpnEmpty = rune('1')
+)
+
+type rpnOperand struct {
+	node regExpNode
+	// pos - column of operand in source string counted from 1, as Token.Pos
+	pos int
+}
+
+// RegExpFromRPN - construct regular expression from reverse polish notation string
+func RegExpFromRPN(pol string) (*RegExp, error) {
+	dict := make(map[rune]struct{})
+
+	for _, r := range pol {
+		switch r {
+		case rpnAdd, rpnMul, rpnClini, rpnEmpty:
+		default:
+			if !unicode.IsSpace(r) {
+				dict[r] = struct{}{}
+			}
+		}
+	}
+
+	return RegExpFromRPNWithDict(pol, dict)
+}
+
+// RegExpFromRPNWithDict - construct regular expression from reverse polish notation string with given alphabet
+func RegExpFromRPNWithDict(pol string, abc map[rune]struct{}) (*RegExp, error) {
+	regexpnode, err := createRegExpNodesRPN([]rune(pol))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RegExp{
+		abc:  abc,
+		tree: regexpnode,
+	}
+
+	return res, nil
+}
+
+func createRegExpNodesRPN(pol []rune) (regExpNode, error) {
+	stack := make([]rpnOperand, 0, len(pol))
+
+	pop := func(pos int) (rpnOperand, error) {
+		if len(stack) == 0 {
+			return rpnOperand{}, fmt.Errorf("stack underflow on column %v", pos)
+		}
+
+		res := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return res, nil
+	}
+
+	for idx, r := range pol {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		pos := idx + 1
+
+		switch r {
+		case rpnAdd, rpnMul:
+			right, err := pop(pos)
+			if err != nil {
+				return nil, err
+			}
+			left, err := pop(pos)
+			if err != nil {
+				return nil, err
+			}
+
+			var node regExpNode
+			if r == rpnAdd {
+				node = regExpNodeAdd{[]regExpNode{left.node, right.node}}
+			} else {
+				node = regExpNodeMul{[]regExpNode{left.node, right.node}}
+			}
+
+			stack = append(stack, rpnOperand{node, left.pos})
+		case rpnClini:
+			operand, err := pop(pos)
+			if err != nil {
+				return nil, err
+			}
+
+			stack = append(stack, rpnOperand{regExpNodeClini{operand.node}, operand.pos})
+		case rpnEmpty:
+			stack = append(stack, rpnOperand{regExpNodeEmptyRune{}, pos})
+		default:
+			stack = append(stack, rpnOperand{regExpNodeRune{r}, pos})
+		}
+	}
+
+	if len(stack) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("unused operand on column %v", stack[1].pos)
+	}
+
+	return stack[0].node, nil
+}
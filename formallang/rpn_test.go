@@ -0,0 +1,52 @@
+package formallang
+
+import "testing"
+
+func TestRegExpFromRPN(t *testing.T) {
+	tests := []struct {
+		pol, want string
+	}{
+		{"a", "a"},
+		{"ab+c*.", "(a + b)c*"},
+		{"ab+c.*", "((a + b)c)*"},
+		{"a 1 +", "a + 1"},
+	}
+
+	for _, test := range tests {
+		reg, err := RegExpFromRPN(test.pol)
+		if err != nil {
+			t.Errorf("%q: %v", test.pol, err)
+			continue
+		}
+
+		if got := reg.ToString(); got != test.want {
+			t.Errorf("%q: got %v, want %v", test.pol, got, test.want)
+		}
+	}
+}
+
+func TestRegExpFromRPNErrors(t *testing.T) {
+	tests := []struct {
+		pol, want string
+	}{
+		{"a+", "stack underflow on column 2"},
+		{"*", "stack underflow on column 1"},
+		{"a b +.", "stack underflow on column 6"},
+		{"ab", "unused operand on column 2"},
+		{"ab.c", "unused operand on column 4"},
+		{"", "empty expression"},
+		{"  ", "empty expression"},
+	}
+
+	for _, test := range tests {
+		_, err := RegExpFromRPN(test.pol)
+		if err == nil {
+			t.Errorf("%q: expected error %q", test.pol, test.want)
+			continue
+		}
+
+		if err.Error() != test.want {
+			t.Errorf("%q: got error %q, want %q", test.pol, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package formallang
+
+import (
+	"maps"
+	"sort"
+)
+
+// EliminationOrder - order in which states are eliminated while building RegExp from automaton
+type EliminationOrder int
+
+const (
+	// EliminateMinWeight - each step eliminates state with the least product of incoming and outgoing transitions
+	EliminateMinWeight EliminationOrder = iota
+	// EliminateBFS - eliminates states in breadth first order from start
+	EliminateBFS
+	// EliminateReverseBFS - eliminates states in reversed breadth first order from start
+	EliminateReverseBFS
+)
+
+type eliminationGraph struct {
+	next  map[*dfanode]map[*dfanode]regExpNode
+	prev  map[*dfanode]map[*dfanode]struct{}
+	index map[*dfanode]int
+}
+
+// sorted - returns nodes of set in order of their index, so result does not depend on map iteration
+func (g *eliminationGraph) sorted(set map[*dfanode]struct{}) []*dfanode {
+	res := make([]*dfanode, 0, len(set))
+	for node := range set {
+		res = append(res, node)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return g.index[res[i]] < g.index[res[j]] })
+	return res
+}
+
+func (g *eliminationGraph) link(from, to *dfanode, node regExpNode) {
+	if g.next[from] == nil {
+		g.next[from] = make(map[*dfanode]regExpNode)
+	}
+	if g.prev[to] == nil {
+		g.prev[to] = make(map[*dfanode]struct{})
+	}
+
+	if prev, ok := g.next[from][to]; ok {
+		node = regExpNodeAdd{[]regExpNode{prev, node}}
+	}
+
+	g.next[from][to] = node
+	g.prev[to][from] = struct{}{}
+}
+
+func (g *eliminationGraph) weight(node *dfanode) int {
+	return len(g.prev[node]) * len(g.next[node])
+}
+
+func (g *eliminationGraph) eliminate(node *dfanode) {
+	var loop regExpNode = regExpNodeEmptyRune{}
+	if self, ok := g.next[node][node]; ok {
+		loop = regExpNodeClini{self}
+	}
+
+	delete(g.next[node], node)
+	delete(g.prev[node], node)
+
+	outs := make(map[*dfanode]struct{})
+	for to := range g.next[node] {
+		outs[to] = struct{}{}
+	}
+	sortedOuts := g.sorted(outs)
+
+	for _, from := range g.sorted(g.prev[node]) {
+		in := g.next[from][node]
+		delete(g.next[from], node)
+
+		for _, to := range sortedOuts {
+			g.link(from, to, regExpNodeMul{[]regExpNode{in, loop, g.next[node][to]}})
+		}
+	}
+
+	for _, to := range sortedOuts {
+		delete(g.prev[to], node)
+	}
+
+	delete(g.next, node)
+	delete(g.prev, node)
+}
+
+// RegExpFromDFA - constructs RegExp describing language of DFA by state elimination
+func RegExpFromDFA(dfa *DFA) *RegExp {
+	return RegExpFromDFAWithOrder(dfa, EliminateMinWeight)
+}
+
+// RegExpFromDFAWithOrder - constructs RegExp describing language of DFA by state elimination in given order
+func RegExpFromDFAWithOrder(dfa *DFA, order EliminationOrder) *RegExp {
+	res := &RegExp{
+		abc:  maps.Clone(dfa.abc),
+		tree: regExpNodeEmptySet{},
+	}
+
+	nodes := dfaNodesBFS(dfa.start)
+	if len(nodes) == 0 {
+		return res
+	}
+
+	g := &eliminationGraph{
+		next:  make(map[*dfanode]map[*dfanode]regExpNode),
+		prev:  make(map[*dfanode]map[*dfanode]struct{}),
+		index: make(map[*dfanode]int),
+	}
+
+	begin, end := &dfanode{}, &dfanode{}
+	g.index[begin] = -1
+	g.index[end] = len(nodes)
+	for i, node := range nodes {
+		g.index[node] = i
+	}
+
+	g.link(begin, dfa.start, regExpNodeEmptyRune{})
+
+	for _, from := range nodes {
+		if from.endpoint {
+			g.link(from, end, regExpNodeEmptyRune{})
+		}
+
+		for _, r := range from.sortedRunes() {
+			g.link(from, from.next[r], regExpNodeRune{r})
+		}
+	}
+
+	switch order {
+	case EliminateBFS:
+		for _, node := range nodes {
+			g.eliminate(node)
+		}
+	case EliminateReverseBFS:
+		for i := len(nodes) - 1; i >= 0; i-- {
+			g.eliminate(nodes[i])
+		}
+	default:
+		left := make(map[*dfanode]struct{}, len(nodes))
+		for _, node := range nodes {
+			left[node] = struct{}{}
+		}
+
+		for len(left) > 0 {
+			var best *dfanode
+			for _, node := range g.sorted(left) {
+				if best == nil || g.weight(node) < g.weight(best) {
+					best = node
+				}
+			}
+
+			g.eliminate(best)
+			delete(left, best)
+		}
+	}
+
+	if node, ok := g.next[begin][end]; ok {
+		res.tree = node.Optimize()
+	}
+
+	return res
+}
+
+// RegExpFromCDFA - constructs RegExp describing language of CDFA by state elimination
+func RegExpFromCDFA(cdfa *CDFA) *RegExp {
+	return RegExpFromDFA(DFAfromCDFA(cdfa))
+}
+
+// RegExpFromCDFAWithOrder - constructs RegExp describing language of CDFA by state elimination in given order
+func RegExpFromCDFAWithOrder(cdfa *CDFA, order EliminationOrder) *RegExp {
+	return RegExpFromDFAWithOrder(DFAfromCDFA(cdfa), order)
+}
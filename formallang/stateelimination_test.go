@@ -0,0 +1,38 @@
+package formallang
+
+import "testing"
+
+func TestRegExpFromCDFAKeepsLanguage(t *testing.T) {
+	for dir, input := range stageInputs(t) {
+		reg := mustRegExp(t, input)
+		cdfa := CDFAfromDFA(DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())).Minimise()
+
+		for _, order := range []EliminationOrder{EliminateMinWeight, EliminateBFS, EliminateReverseBFS} {
+			res := mustRegExp(t, RegExpFromCDFAWithOrder(cdfa, order).ToString())
+			if minimalDOT(t, reg) != minimalDOT(t, res) {
+				t.Errorf("%v: %v eliminated to %v", dir, input, res.ToString())
+			}
+		}
+	}
+}
+
+func TestZeroIsSymbolInClassicSyntax(t *testing.T) {
+	reg := mustRegExp(t, "a0*")
+	if _, ok := reg.abc['0']; !ok {
+		t.Errorf("0 is not in alphabet of %v", reg.ToString())
+	}
+	if !NFAFromRegExp(reg).Accepts("a00") {
+		t.Errorf("%v doesn't accept a00", reg.ToString())
+	}
+
+	empty := CDFAfromDFA(DFAfromNFA(NFAFromRegExp(mustRegExp(t, "a")).RemoveEmpty())).Intersect(
+		CDFAfromDFA(DFAfromNFA(NFAFromRegExp(mustRegExp(t, "b")).RemoveEmpty())))
+
+	str := RegExpFromCDFA(empty).ToString()
+	if str != "[]" {
+		t.Errorf("empty language is written as %v", str)
+	}
+	if NFAFromRegExp(mustRegExp(t, str)).Accepts("") {
+		t.Errorf("%v accepts empty word", str)
+	}
+}
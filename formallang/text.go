@@ -0,0 +1,391 @@
+package formallang
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// textEmptyAlias - ascii spelling of empty transition
+	textEmptyAlias = "eps"
+	// textEmptyLegacy - spelling of empty transition by EmptyRune
+	textEmptyLegacy = string(EmptyRune)
+	textNoStates    = "-"
+	textComment     = "#"
+	textEscape      = '\\'
+)
+
+// textSymbol - decodes single rune field, escaped by backslash or not
+func textSymbol(field string) (rune, bool) {
+	if len(field) > 1 && field[0] == textEscape {
+		field = field[1:]
+	}
+
+	r, size := utf8.DecodeRuneInString(field)
+	return r, size > 0 && size == len(field)
+}
+
+// textRune - encodes rune, so it can't be read as comment, placeholder, escape or empty transition
+func textRune(r rune) string {
+	switch str := string(r); str {
+	case textNoStates, textComment, string(textEscape), emptySymbol, textEmptyLegacy:
+		return string(textEscape) + str
+	default:
+		return str
+	}
+}
+
+type textEdge struct {
+	from, to string
+	r        rune
+	empty    bool
+	line     int
+}
+
+// textAutomaton - automaton description read from text format
+type textAutomaton struct {
+	abc    map[rune]struct{}
+	states []string
+	// lines - line where state appears first
+	lines     map[string]int
+	start     string
+	startLine int
+	accept    []string
+	edges     []textEdge
+}
+
+func (descr *textAutomaton) addState(id string, line int) {
+	if _, ok := descr.lines[id]; ok {
+		return
+	}
+
+	descr.lines[id] = line
+	descr.states = append(descr.states, id)
+}
+
+// checkStart - start state is required by all automata except DFA
+func (descr *textAutomaton) checkStart() error {
+	if descr.start == "" {
+		return fmt.Errorf("line %v: start state is missing", descr.startLine)
+	}
+
+	return nil
+}
+
+// parseText - reads automaton description:
+// alphabet line, start state, accepting states, then one "from symbol to" line per transition,
+// lines starting with # are comments, "-" stands for empty alphabet, start or accepting states,
+// symbols "-", "#", "\", "ε" and "$" are escaped by backslash
+func parseText(reader io.Reader, allowEmpty bool) (*textAutomaton, error) {
+	descr := &textAutomaton{
+		abc:   make(map[rune]struct{}),
+		lines: make(map[string]int),
+	}
+
+	scanner := bufio.NewScanner(reader)
+	lineno := 0
+	header := 0
+	for scanner.Scan() {
+		lineno++
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && strings.HasPrefix(fields[0], textComment) {
+			continue
+		}
+
+		switch header {
+		case 0:
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) == 1 && fields[0] == textNoStates {
+				fields = nil
+			}
+
+			for _, field := range fields {
+				r, ok := textSymbol(field)
+				if !ok {
+					return nil, fmt.Errorf("line %v: alphabet symbol %q is not a single rune", lineno, field)
+				}
+				if field == emptySymbol || field == textEmptyLegacy {
+					return nil, fmt.Errorf("line %v: %q in alphabet must be escaped as %q", lineno, field, textRune(r))
+				}
+
+				descr.abc[r] = struct{}{}
+			}
+		case 1:
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("line %v: expected one start state, got %v", lineno, len(fields))
+			}
+
+			descr.startLine = lineno
+			if fields[0] != textNoStates {
+				descr.start = fields[0]
+				descr.addState(descr.start, lineno)
+			}
+		case 2:
+			if len(fields) == 1 && fields[0] == textNoStates {
+				fields = nil
+			}
+
+			for _, field := range fields {
+				descr.accept = append(descr.accept, field)
+				descr.addState(field, lineno)
+			}
+		default:
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %v: expected \"from symbol to\", got %v fields", lineno, len(fields))
+			}
+
+			var r rune
+			empty := false
+			switch symbol := fields[1]; {
+			case symbol == emptySymbol || symbol == textEmptyAlias || symbol == textEmptyLegacy:
+				if !allowEmpty {
+					return nil, fmt.Errorf("line %v: empty transitions are not allowed", lineno)
+				}
+				empty = true
+			default:
+				var ok bool
+				r, ok = textSymbol(symbol)
+				if !ok {
+					return nil, fmt.Errorf("line %v: symbol %q is not a single rune", lineno, symbol)
+				}
+				if _, ok := descr.abc[r]; !ok {
+					return nil, fmt.Errorf("line %v: symbol %q is not in alphabet", lineno, symbol)
+				}
+			}
+
+			descr.addState(fields[0], lineno)
+			descr.addState(fields[2], lineno)
+			descr.edges = append(descr.edges, textEdge{fields[0], fields[2], r, empty, lineno})
+		}
+
+		header++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if header < 3 {
+		return nil, fmt.Errorf("line %v: unexpected end of input, alphabet, start and accepting states are required", lineno)
+	}
+
+	return descr, nil
+}
+
+// dfanodes - creates deterministic nodes, returns them by state names
+func (descr *textAutomaton) dfanodes(newNode func() *dfanode) (map[string]*dfanode, error) {
+	nodes := make(map[string]*dfanode, len(descr.states))
+	for _, id := range descr.states {
+		nodes[id] = newNode()
+	}
+
+	for _, id := range descr.accept {
+		nodes[id].endpoint = true
+	}
+
+	for _, edge := range descr.edges {
+		from := nodes[edge.from]
+		if _, ok := from.next[edge.r]; ok {
+			return nil, fmt.Errorf("line %v: state %q already has transition by %q", edge.line, edge.from, edge.r)
+		}
+
+		from.link(edge.r, nodes[edge.to])
+	}
+
+	return nodes, nil
+}
+
+// ParseNFA - reads NFA in text format, "ε", "eps" or "$" symbol means empty transition, "\ε" and "\$" are letters,
+// start state is required
+func ParseNFA(reader io.Reader) (*NFA, error) {
+	descr, err := parseText(reader, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := descr.checkStart(); err != nil {
+		return nil, err
+	}
+
+	accept := make(map[string]struct{}, len(descr.accept))
+	for _, id := range descr.accept {
+		accept[id] = struct{}{}
+	}
+
+	builder := NewNFABuilder(descr.abc)
+	for _, id := range descr.states {
+		builder.State(id)
+
+		if id == descr.start {
+			builder.Start()
+		}
+		if _, ok := accept[id]; ok {
+			builder.Accept()
+		}
+	}
+
+	for _, edge := range descr.edges {
+		if edge.empty {
+			builder.EmptyEdge(edge.from, edge.to)
+		} else {
+			builder.Edge(edge.from, edge.r, edge.to)
+		}
+	}
+
+	return builder.Build()
+}
+
+// ParseDFA - reads DFA in text format, start state "-" means DFA without states, that accepts nothing
+func ParseDFA(reader io.Reader) (*DFA, error) {
+	descr, err := parseText(reader, false)
+	if err != nil {
+		return nil, err
+	}
+
+	dfa := &DFA{
+		abc:   maps.Clone(descr.abc),
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	nodes, err := descr.dfanodes(dfa.newNode)
+	if err != nil {
+		return nil, err
+	}
+
+	if descr.start != "" {
+		dfa.start = nodes[descr.start]
+	}
+	return dfa, nil
+}
+
+// ParseCDFA - reads CDFA in text format, every state must have transition by every symbol,
+// start state is required, since even empty language has sink state in CDFA
+func ParseCDFA(reader io.Reader) (*CDFA, error) {
+	descr, err := parseText(reader, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := descr.checkStart(); err != nil {
+		return nil, err
+	}
+
+	cdfa := &CDFA{
+		abc:   maps.Clone(descr.abc),
+		nodes: make(map[*dfanode]struct{}),
+	}
+
+	nodes, err := descr.dfanodes(cdfa.newNode)
+	if err != nil {
+		return nil, err
+	}
+
+	alph := unionAlphabet(descr.abc)
+	for _, id := range descr.states {
+		for _, r := range alph {
+			if _, ok := nodes[id].next[r]; !ok {
+				return nil, fmt.Errorf("line %v: state %q has no transition by %q", descr.lines[id], id, r)
+			}
+		}
+	}
+
+	cdfa.start = nodes[descr.start]
+
+	for _, id := range descr.states {
+		node := nodes[id]
+		if node.endpoint {
+			continue
+		}
+
+		stock := true
+		for _, to := range node.next {
+			stock = stock && to == node
+		}
+
+		if stock {
+			cdfa.stock = node
+			break
+		}
+	}
+
+	if cdfa.stock == nil {
+		cdfa.stock = cdfa.newNode()
+		for _, r := range alph {
+			cdfa.stock.link(r, cdfa.stock)
+		}
+	}
+
+	return cdfa, nil
+}
+
+// writeText - writes automaton in text format
+func writeText(w io.Writer, abc map[rune]struct{}, states []dotState, edges []dotEdge) error {
+	builder := &strings.Builder{}
+
+	alph := make([]string, 0, len(abc))
+	for _, r := range unionAlphabet(abc) {
+		alph = append(alph, textRune(r))
+	}
+	if len(alph) == 0 {
+		alph = append(alph, textNoStates)
+	}
+	builder.WriteString(strings.Join(alph, " "))
+	builder.WriteRune('\n')
+
+	start := textNoStates
+	accept := make([]string, 0)
+	for _, state := range states {
+		if state.start {
+			start = state.name
+		}
+		if state.endpoint {
+			accept = append(accept, state.name)
+		}
+	}
+	builder.WriteString(start)
+	builder.WriteRune('\n')
+
+	if len(accept) == 0 {
+		accept = append(accept, textNoStates)
+	}
+	builder.WriteString(strings.Join(accept, " "))
+	builder.WriteRune('\n')
+
+	for _, edge := range edges {
+		if edge.empty {
+			fmt.Fprintf(builder, "%s %s %s\n", states[edge.from].name, emptySymbol, states[edge.to].name)
+		}
+		for _, r := range edge.runes {
+			fmt.Fprintf(builder, "%s %s %s\n", states[edge.from].name, textRune(r), states[edge.to].name)
+		}
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// WriteText - writes NFA in text format
+func (nfa *NFA) WriteText(w io.Writer) error {
+	states, edges := nfa.dot()
+	return writeText(w, nfa.abc, states, edges)
+}
+
+// WriteText - writes DFA in text format
+func (dfa *DFA) WriteText(w io.Writer) error {
+	states, edges := dfaDOT(dfa.start, nil)
+	return writeText(w, dfa.abc, states, edges)
+}
+
+// WriteText - writes CDFA in text format
+func (cdfa *CDFA) WriteText(w io.Writer) error {
+	states, edges := dfaDOT(cdfa.start, cdfa.stock)
+	return writeText(w, cdfa.abc, states, edges)
+}
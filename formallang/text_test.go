@@ -0,0 +1,162 @@
+package formallang
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// checkTextRoundTrip - parses written automaton and checks, that it is written the same way
+func checkTextRoundTrip[T interface{ WriteText(io.Writer) error }](t *testing.T, name string, src T, parse func(io.Reader) (T, error)) T {
+	t.Helper()
+
+	text := textString(t, src)
+	dst, err := parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("%v: %v\n%v", name, err, text)
+	}
+
+	if again := textString(t, dst); text != again {
+		t.Errorf("%v: round trip changed text\n%v\n%v", name, text, again)
+	}
+
+	return dst
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	inputs := stageInputs(t)
+	// empty alphabet
+	inputs["one"] = "1"
+
+	for name, input := range inputs {
+		reg := mustRegExp(t, input)
+		dfa := DFAfromNFA(NFAFromRegExp(reg).RemoveEmpty())
+
+		checkTextRoundTrip(t, name+" nfa", NFAFromRegExp(reg), ParseNFA)
+		checkTextRoundTrip(t, name+" dfa", dfa, ParseDFA)
+		checkTextRoundTrip(t, name+" cdfa", CDFAfromDFA(dfa), ParseCDFA)
+		checkTextRoundTrip(t, name+" mcdfa", CDFAfromDFA(dfa).Minimise(), ParseCDFA)
+	}
+}
+
+func TestTextRoundTripServiceSymbols(t *testing.T) {
+	abc := map[rune]struct{}{'#': {}, '-': {}, '\\': {}, '$': {}, 'a': {}}
+	nfa, err := NewNFABuilder(abc).
+		State("p").Start().
+		State("q").Accept().
+		Edge("p", '#', "q").
+		Edge("p", '-', "q").
+		Edge("p", '\\', "q").
+		Edge("p", '$', "q").
+		Edge("q", 'a', "p").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := checkTextRoundTrip(t, "nfa", nfa, ParseNFA)
+	for _, word := range []string{"#", "-", "\\", "$", "#a$"} {
+		if !parsed.Accepts(word) {
+			t.Errorf("parsed nfa doesn't accept %q", word)
+		}
+	}
+	if parsed.Accepts("a") {
+		t.Errorf("parsed nfa accepts %q", "a")
+	}
+}
+
+func TestTextRoundTripEmptyLanguage(t *testing.T) {
+	cdfa := emptyLanguage(t)
+
+	dfa := checkTextRoundTrip(t, "dfa", DFAfromCDFA(cdfa), ParseDFA)
+	if dfa.start != nil {
+		t.Errorf("parsed dfa has start state")
+	}
+
+	checkTextRoundTrip(t, "cdfa", cdfa, ParseCDFA)
+}
+
+func TestParseTextPlaceholders(t *testing.T) {
+	const text = `# comment before header
+-
+-
+-
+`
+	dfa, err := ParseDFA(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dfa.abc) != 0 || dfa.start != nil {
+		t.Errorf("expected empty dfa, got alphabet %v", dfa.abc)
+	}
+
+	if _, err := ParseNFA(strings.NewReader(text)); err == nil {
+		t.Errorf("nfa without start state is parsed")
+	}
+	if _, err := ParseCDFA(strings.NewReader(text)); err == nil {
+		t.Errorf("cdfa without start state is parsed")
+	}
+}
+
+func TestTextLiteralEpsilon(t *testing.T) {
+	// ε is letter here, Thompson NFA also has empty transitions
+	nfa := NFAFromRegExp(mustRegExp(t, "(ε + 1)a"))
+
+	parsed := checkTextRoundTrip(t, "nfa", nfa, ParseNFA)
+	if want, got := dotString(t, nfa), dotString(t, parsed); want != got {
+		t.Errorf("parsed nfa differs\n%v\n%v", want, got)
+	}
+
+	for word, want := range map[string]bool{"a": true, "εa": true, "εεa": false, "ε": false} {
+		if got := parsed.Accepts(word); got != want {
+			t.Errorf("parsed nfa accepts %q: %v, want %v", word, got, want)
+		}
+	}
+
+	if _, err := ParseNFA(strings.NewReader("a ε\nq0\n-\n")); err == nil {
+		t.Errorf("unescaped ε in alphabet is parsed")
+	}
+}
+
+func TestTextLegacyEmptySymbol(t *testing.T) {
+	// "$" is read as empty transition like EmptyRune was, "\$" is the letter
+	nfa, err := ParseNFA(strings.NewReader("a \\$\nq0\nq2\nq0 $ q1\nq1 a q2\nq1 \\$ q2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for word, want := range map[string]bool{"a": true, "$": true, "$a": false, "": false} {
+		if got := nfa.Accepts(word); got != want {
+			t.Errorf("parsed nfa accepts %q: %v, want %v", word, got, want)
+		}
+	}
+
+	if _, err := ParseNFA(strings.NewReader("a $\nq0\n-\n")); err == nil {
+		t.Errorf("unescaped $ in alphabet is parsed")
+	}
+	if _, err := ParseDFA(strings.NewReader("a\nq0\n-\nq0 $ q0\n")); err == nil {
+		t.Errorf("empty transition of dfa is parsed")
+	}
+}
+
+func TestParseCDFAErrorLines(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"a b\nq0\nq1\nq0 a q1\nq0 b q0\nq1 a q1\n", `line 3: state "q1" has no transition by 'b'`},
+		{"a\nq0\n-\nq0 a q1\n", `line 4: state "q1" has no transition by 'a'`},
+		{"# comment\na\n-\n-\n", "line 3: start state is missing"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseCDFA(strings.NewReader(test.text))
+		if err == nil {
+			t.Errorf("%q: expected error %q", test.text, test.want)
+			continue
+		}
+
+		if err.Error() != test.want {
+			t.Errorf("%q: got error %q, want %q", test.text, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package formallang
+
+import (
+	"fmt"
+	"unicode"
+)
+
+const escapeRune = rune('\\')
+
+// Syntax - set of service symbols of regular expression
+type Syntax int
+
+const (
+	// SyntaxClassic - + is alternation, * is the only postfix operator, 0 is ordinary symbol
+	SyntaxClassic Syntax = iota
+	// SyntaxExtended - | is alternation, postfix operators are *, +, ? and {n}, {n,}, {n,m}, 0 is empty set
+	SyntaxExtended
+)
+
+func (syntax Syntax) alternation() rune {
+	if syntax == SyntaxExtended {
+		return '|'
+	}
+
+	return '+'
+}
+
+func isServiceRune(r rune, syntax Syntax) bool {
+	switch r {
+	case '*', '(', ')', '1', '[', ']', '.', '&', '~', syntax.alternation():
+		return true
+	case '+', '?', '{', '}', '0':
+		return syntax == SyntaxExtended
+	}
+
+	return false
+}
+
+// isClassServiceRune - service symbols inside character class, ^ is service only right after [
+func isClassServiceRune(r rune, first bool) bool {
+	return r == ']' || r == '-' || (r == '^' && first)
+}
+
+// Tokenize - slices string into tokens, backslash makes next symbol literal
+func Tokenize(str string) ([]Token, error) {
+	return TokenizeWithSyntax(str, SyntaxClassic)
+}
+
+// TokenizeWithSyntax - slices string into tokens of given syntax, backslash makes next symbol literal
+func TokenizeWithSyntax(str string, syntax Syntax) ([]Token, error) {
+	tokens := make([]Token, 0, len(str))
+	escaped := false
+	escapePos := 0
+
+	inClass := false
+	classFirst := false
+
+	pos := 0
+	for _, r := range str {
+		pos++
+
+		if escaped {
+			tokens = append(tokens, Token{Symb: r, Servicable: false, Pos: escapePos})
+			escaped = false
+			classFirst = false
+			continue
+		}
+
+		if r == escapeRune {
+			escaped = true
+			escapePos = pos
+			continue
+		}
+
+		if unicode.IsSpace(r) {
+			continue
+		}
+
+		if inClass {
+			servicable := isClassServiceRune(r, classFirst)
+			tokens = append(tokens, Token{Symb: r, Servicable: servicable, Pos: pos})
+
+			inClass = !(servicable && r == ']')
+			classFirst = false
+			continue
+		}
+
+		servicable := isServiceRune(r, syntax)
+		tokens = append(tokens, Token{Symb: r, Servicable: servicable, Pos: pos})
+
+		if servicable && r == '[' {
+			inClass = true
+			classFirst = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("unfinished escape on column %v", escapePos)
+	}
+
+	return tokens, nil
+}
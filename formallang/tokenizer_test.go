@@ -0,0 +1,55 @@
+package formallang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		str  string
+		want []Token
+	}{
+		{"a+b", []Token{{'a', false, 1}, {'+', true, 2}, {'b', false, 3}}},
+		{`\+`, []Token{{'+', false, 1}}},
+		{`\1a`, []Token{{'1', false, 1}, {'a', false, 3}}},
+		{`\\`, []Token{{'\\', false, 1}}},
+		{`\ `, []Token{{' ', false, 1}}},
+		{" a \t b*\n", []Token{{'a', false, 2}, {'b', false, 6}, {'*', true, 7}}},
+		{"", []Token{}},
+	}
+
+	for _, test := range tests {
+		got, err := Tokenize(test.str)
+		if err != nil {
+			t.Errorf("%q: %v", test.str, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.str, got, test.want)
+		}
+	}
+}
+
+func TestTokenizeUnfinishedEscape(t *testing.T) {
+	tests := []struct {
+		str, want string
+	}{
+		{`\`, "unfinished escape on column 1"},
+		{`ab\`, "unfinished escape on column 3"},
+		{`\\\`, "unfinished escape on column 3"},
+	}
+
+	for _, test := range tests {
+		_, err := Tokenize(test.str)
+		if err == nil {
+			t.Errorf("%q: expected error %q", test.str, test.want)
+			continue
+		}
+
+		if err.Error() != test.want {
+			t.Errorf("%q: got error %q, want %q", test.str, err, test.want)
+		}
+	}
+}
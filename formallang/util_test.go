@@ -0,0 +1,138 @@
+package formallang
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stagesDir - directory with testN subdirectories relative to package
+const stagesDir = "../test"
+
+// stageInputs - regular expressions of test/testN/input.txt by directory
+func stageInputs(t testing.TB) map[string]string {
+	t.Helper()
+
+	dirs, err := filepath.Glob(filepath.Join(stagesDir, "test*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := make(map[string]string)
+	for _, dir := range dirs {
+		input, err := os.ReadFile(filepath.Join(dir, "input.txt"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res[dir] = strings.TrimSpace(string(input))
+	}
+
+	if len(res) == 0 {
+		t.Fatalf("no inputs in %v", stagesDir)
+	}
+
+	return res
+}
+
+func mustRegExp(t testing.TB, str string) *RegExp {
+	t.Helper()
+
+	return mustRegExpWithSyntax(t, str, SyntaxClassic)
+}
+
+func mustRegExpWithSyntax(t testing.TB, str string, syntax Syntax) *RegExp {
+	t.Helper()
+
+	tokens, err := TokenizeWithSyntax(str, syntax)
+	if err != nil {
+		t.Fatalf("%v: %v", str, err)
+	}
+
+	reg, err := RegExpFromTokensWithSyntax(tokens, syntax)
+	if err != nil {
+		t.Fatalf("%v: %v", str, err)
+	}
+
+	return reg
+}
+
+// mustDFA - DFA of classic expression built through Thompson NFA
+func mustDFA(t testing.TB, str string) *DFA {
+	t.Helper()
+
+	return DFAfromNFA(NFAFromRegExp(mustRegExp(t, str)).RemoveEmpty())
+}
+
+// words - all words over alphabet not longer than n
+func words(abc []rune, n int) []string {
+	res := []string{""}
+	layer := []string{""}
+	for i := 0; i < n; i++ {
+		next := make([]string, 0, len(layer)*len(abc))
+		for _, word := range layer {
+			for _, r := range abc {
+				next = append(next, word+string(r))
+			}
+		}
+
+		res = append(res, next...)
+		layer = next
+	}
+
+	return res
+}
+
+// dotString - WriteDOT output of automaton
+func dotString(t testing.TB, automaton interface{ WriteDOT(io.Writer) error }) string {
+	t.Helper()
+
+	builder := &strings.Builder{}
+	if err := automaton.WriteDOT(builder); err != nil {
+		t.Fatal(err)
+	}
+
+	return builder.String()
+}
+
+// textString - WriteText output of automaton
+func textString(t testing.TB, automaton interface{ WriteText(io.Writer) error }) string {
+	t.Helper()
+
+	builder := &strings.Builder{}
+	if err := automaton.WriteText(builder); err != nil {
+		t.Fatal(err)
+	}
+
+	return builder.String()
+}
+
+// regExpInputs - stage inputs and n random expressions over a, b and 1
+func regExpInputs(t testing.TB, n int) []string {
+	t.Helper()
+
+	inputs := make([]string, 0)
+	for _, input := range stageInputs(t) {
+		inputs = append(inputs, input)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < n; i++ {
+		inputs = append(inputs, randomRegExp(rng, 5))
+	}
+
+	return inputs
+}
+
+// minimalDFADOT - WriteDOT output of minimal CDFA, equal for DFAs of the same language over the same alphabet
+func minimalDFADOT(t testing.TB, dfa *DFA) string {
+	t.Helper()
+
+	return dotString(t, CDFAfromDFA(dfa).Minimise())
+}
@@ -2,16 +2,50 @@ package main
 
 import (
 	"fmt"
-
-	fl "github.com/karetskiiVO/FormalLanguages/formallang"
+	"os"
 )
 
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"convert", "convert [-rpn] [-extended] [-to nfa|dfa|cdfa|mcdfa] [-format text|json|dot|png|svg|jpg] [-o file] regexp", runConvert},
+	{"match", "match [-rpn] [-extended] regexp < words", runMatch},
+	{"equiv", "equiv [-rpn] [-extended] regexp regexp", runEquiv},
+	{"min", "min [-format text|json|dot|png|svg|jpg] [-o file] file|-", runMin},
+	{"stages", "stages [-rpn] [-extended] [-format png|svg|jpg|dot] regexp dir", runStages},
+	{"prefix", "prefix < \"rpn letter k\"", runPrefix},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "\t%v\n", cmd.usage)
+	}
+}
+
 func main() {
-	var pol string
-	var r rune
-	var k int
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
 
-	fmt.Scanf("%s %c %d", &pol, &r, &k)
+		if err := cmd.run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", cmd.name, err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+	usage()
+	os.Exit(2)
 }